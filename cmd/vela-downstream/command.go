@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/go-vela/vela-downstream/downstream"
+)
+
+// trigger triggers the builds for the downstream repos
+// and waits on them when reporting back is enabled.
+func trigger(ctx context.Context, c *cli.Command) error {
+	// create the plugin
+	p, redactor, err := setup(c)
+	if err != nil {
+		return err
+	}
+
+	// validate the plugin
+	err = p.Validate()
+	if err != nil {
+		return redactor.Error(err)
+	}
+
+	// trigger the builds for the downstream repos
+	triggered, err := p.Trigger(ctx)
+	if err != nil {
+		return redactor.Error(err)
+	}
+
+	// record the triggered builds for the other commands
+	err = writeBuilds(c.String("builds.file"), triggered)
+	if err != nil {
+		return redactor.Error(err)
+	}
+
+	// early exit if reporting back is not enabled
+	if !p.Build.Report || len(triggered) == 0 {
+		return nil
+	}
+
+	return redactor.Error(p.Wait(ctx, triggered))
+}
+
+// wait waits on the provided builds without triggering them.
+func wait(ctx context.Context, c *cli.Command) error {
+	// create the plugin
+	p, redactor, err := setup(c)
+	if err != nil {
+		return err
+	}
+
+	// capture the builds to wait on
+	triggered, err := builds(c)
+	if err != nil {
+		return err
+	}
+
+	// validate the plugin
+	err = p.ValidateTriggered(triggered)
+	if err != nil {
+		return redactor.Error(err)
+	}
+
+	return redactor.Error(p.Wait(ctx, triggered))
+}
+
+// status reports the current status of the provided builds.
+func status(ctx context.Context, c *cli.Command) error {
+	// create the plugin
+	p, redactor, err := setup(c)
+	if err != nil {
+		return err
+	}
+
+	// capture the builds to report
+	triggered, err := builds(c)
+	if err != nil {
+		return err
+	}
+
+	// validate the plugin
+	err = p.ValidateTriggered(triggered)
+	if err != nil {
+		return redactor.Error(err)
+	}
+
+	// create the output with secret values masked
+	out := redactor.Writer(os.Stdout)
+	defer out.Close()
+
+	return redactor.Error(p.Status(ctx, triggered, out))
+}
+
+// cancel cancels the provided builds.
+func cancel(ctx context.Context, c *cli.Command) error {
+	// create the plugin
+	p, redactor, err := setup(c)
+	if err != nil {
+		return err
+	}
+
+	// capture the builds to cancel
+	triggered, err := builds(c)
+	if err != nil {
+		return err
+	}
+
+	// validate the plugin
+	err = p.ValidateTriggered(triggered)
+	if err != nil {
+		return redactor.Error(err)
+	}
+
+	return redactor.Error(p.Cancel(ctx, triggered))
+}
+
+// builds parses the builds provided as arguments, with the builds flag
+// or, when neither are provided, from the builds file.
+func builds(c *cli.Command) ([]*downstream.Triggered, error) {
+	names := append(c.StringSlice("builds.names"), c.Args().Slice()...)
+
+	// check if builds should be read from the builds file
+	if len(names) == 0 && len(c.String("builds.file")) > 0 {
+		logrus.Debugf("reading builds from %s", c.String("builds.file"))
+
+		f, err := os.Open(c.String("builds.file"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read builds file: %w", downstream.ErrConfig, err)
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if len(line) > 0 {
+				names = append(names, line)
+			}
+		}
+
+		err = scanner.Err()
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read builds file: %w", downstream.ErrConfig, err)
+		}
+	}
+
+	return downstream.ParseTriggered(names...)
+}
+
+// writeBuilds writes the provided triggered builds to the
+// builds file, one per line, when a builds file is provided.
+func writeBuilds(path string, triggered []*downstream.Triggered) error {
+	// early exit if no builds file is provided
+	if len(path) == 0 {
+		return nil
+	}
+
+	logrus.Debugf("writing triggered builds to %s", path)
+
+	var b strings.Builder
+
+	for _, t := range triggered {
+		fmt.Fprintln(&b, t)
+	}
+
+	//nolint:gosec // builds file is shared with other steps of the pipeline
+	err := os.WriteFile(path, []byte(b.String()), 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to write builds file: %w", err)
+	}
+
+	return nil
+}
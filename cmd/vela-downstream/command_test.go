@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/go-vela/vela-downstream/downstream"
+)
+
+// parseBuilds runs a command with the builds flags
+// and the provided arguments to capture the builds.
+func parseBuilds(t *testing.T, args ...string) ([]*downstream.Triggered, error) {
+	t.Helper()
+
+	var (
+		got []*downstream.Triggered
+		err error
+	)
+
+	cmd := &cli.Command{
+		Name: "vela-downstream",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{Name: "builds.names"},
+			&cli.StringFlag{Name: "builds.file"},
+		},
+		Action: func(_ context.Context, c *cli.Command) error {
+			got, err = builds(c)
+
+			return nil
+		},
+	}
+
+	runErr := cmd.Run(context.Background(), append([]string{"vela-downstream"}, args...))
+	if runErr != nil {
+		t.Fatalf("Run returned err: %v", runErr)
+	}
+
+	return got, err
+}
+
+func TestDownstream_builds(t *testing.T) {
+	// setup types
+	path := filepath.Join(t.TempDir(), "builds")
+
+	triggered, err := downstream.ParseTriggered("go-vela/hello-world/2", "prod:go-vela/hello-go/5")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	err = writeBuilds(path, triggered)
+	if err != nil {
+		t.Errorf("writeBuilds returned err: %v", err)
+	}
+
+	// run tests
+	got, err := parseBuilds(t, "--builds.file", path)
+	if err != nil {
+		t.Errorf("builds returned err: %v", err)
+	}
+
+	if len(got) != 2 || got[0].String() != "go-vela/hello-world/2" || got[1].String() != "prod:go-vela/hello-go/5" {
+		t.Errorf("builds from file is %v, want %v", got, triggered)
+	}
+
+	got, err = parseBuilds(t, "--builds.file", path, "--builds.names", "go-vela/hello-world/7", "go-vela/hello-world/8")
+	if err != nil {
+		t.Errorf("builds returned err: %v", err)
+	}
+
+	if len(got) != 2 || got[0].String() != "go-vela/hello-world/7" || got[1].String() != "go-vela/hello-world/8" {
+		t.Errorf("builds from flag and arguments is %v, want builds 7 and 8", got)
+	}
+}
+
+func TestDownstream_builds_Failure(t *testing.T) {
+	// run test
+	_, err := parseBuilds(t, "--builds.file", filepath.Join(t.TempDir(), "missing"))
+	if !errors.Is(err, downstream.ErrConfig) {
+		t.Errorf("builds returned err %v, want %v", err, downstream.ErrConfig)
+	}
+}
+
+func TestDownstream_writeBuilds_NoFile(t *testing.T) {
+	// run test
+	err := writeBuilds("", nil)
+	if err != nil {
+		t.Errorf("writeBuilds returned err: %v", err)
+	}
+
+	_, err = os.Stat("builds")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("writeBuilds should not have written a file")
+	}
+}
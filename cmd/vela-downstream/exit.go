@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+
+	"github.com/go-vela/vela-downstream/downstream"
+)
+
+const (
+	// ExitFailure defines the exit code for failures
+	// that do not match any other category.
+	ExitFailure = 1
+
+	// ExitConfig defines the exit code for
+	// an invalid plugin configuration.
+	ExitConfig = 2
+
+	// ExitAuth defines the exit code for failures
+	// authenticating or authorizing with Vela.
+	ExitAuth = 3
+
+	// ExitBuildNotFound defines the exit code for
+	// no build being found to restart.
+	ExitBuildNotFound = 4
+
+	// ExitTrigger defines the exit code for failures
+	// restarting a downstream build.
+	ExitTrigger = 5
+
+	// ExitDownstreamFailed defines the exit code for a triggered
+	// build completing without matching the target statuses.
+	ExitDownstreamFailed = 6
+
+	// ExitReportTimeout defines the exit code for the triggered
+	// builds not completing before the build check timeout.
+	ExitReportTimeout = 7
+
+	// ExitLoop defines the exit code for refusing to trigger
+	// repos that would create a cyclic downstream chain.
+	ExitLoop = 8
+)
+
+// exitCode returns the exit code for the category of the provided error.
+//
+// When an error matches multiple categories, i.e. from the
+// preflight checks, the first category in this order is used.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, downstream.ErrConfig):
+		return ExitConfig
+	case errors.Is(err, downstream.ErrLoop):
+		return ExitLoop
+	case errors.Is(err, downstream.ErrUnauthorized), errors.Is(err, downstream.ErrForbidden):
+		return ExitAuth
+	case errors.Is(err, downstream.ErrBuildNotFound):
+		return ExitBuildNotFound
+	case errors.Is(err, downstream.ErrReportTimeout):
+		return ExitReportTimeout
+	case errors.Is(err, downstream.ErrDownstreamFailed):
+		return ExitDownstreamFailed
+	case errors.Is(err, downstream.ErrTrigger):
+		return ExitTrigger
+	default:
+		return ExitFailure
+	}
+}
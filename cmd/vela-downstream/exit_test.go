@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-vela/vela-downstream/downstream"
+)
+
+func TestDownstream_exitCode(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "success",
+			want: 0,
+		},
+		{
+			name: "failure",
+			err:  errors.New("failure"),
+			want: ExitFailure,
+		},
+		{
+			name: "config",
+			err:  fmt.Errorf("%w: no config token provided", downstream.ErrConfig),
+			want: ExitConfig,
+		},
+		{
+			name: "unauthorized",
+			err:  &downstream.APIError{Kind: downstream.ErrUnauthorized, Err: errors.New("unauthorized")},
+			want: ExitAuth,
+		},
+		{
+			name: "forbidden trigger",
+			err:  fmt.Errorf("%w: %w", downstream.ErrTrigger, &downstream.APIError{Kind: downstream.ErrForbidden, Err: errors.New("forbidden")}),
+			want: ExitAuth,
+		},
+		{
+			name: "build not found",
+			err:  errors.Join(fmt.Errorf("%w: go-vela/hello-world", downstream.ErrBuildNotFound), errors.New("failure")),
+			want: ExitBuildNotFound,
+		},
+		{
+			name: "trigger",
+			err:  fmt.Errorf("%w: %w", downstream.ErrTrigger, &downstream.APIError{Kind: downstream.ErrServer, Err: errors.New("server error")}),
+			want: ExitTrigger,
+		},
+		{
+			name: "downstream failed",
+			err:  fmt.Errorf("%w: triggered build go-vela/hello-world/1 returned failure status", downstream.ErrDownstreamFailed),
+			want: ExitDownstreamFailed,
+		},
+		{
+			name: "report timeout",
+			err:  fmt.Errorf("%w while awaiting downstream build statuses", downstream.ErrReportTimeout),
+			want: ExitReportTimeout,
+		},
+		{
+			name: "loop",
+			err:  fmt.Errorf("refusing to trigger repos:\n%w", fmt.Errorf("%w: go-vela/hello-world is the upstream repo", downstream.ErrLoop)),
+			want: ExitLoop,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := exitCode(test.err)
+
+			if got != test.want {
+				t.Errorf("exitCode is %d, want %d", got, test.want)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 
 	"github.com/go-vela/server/constants"
+	"github.com/go-vela/vela-downstream/downstream"
 	"github.com/go-vela/vela-downstream/version"
 )
 
@@ -46,6 +47,36 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 		},
 		Version: v.Semantic(),
 		Action:  run,
+		Commands: []*cli.Command{
+			{
+				Name:   "trigger",
+				Usage:  "Trigger builds for the downstream repos and optionally wait on them",
+				Action: trigger,
+			},
+			{
+				Name:      "wait",
+				Usage:     "Wait on builds until they match the build check statuses without triggering them",
+				ArgsUsage: "[<profile>:]<org>/<repo>/<number>...",
+				Action:    wait,
+			},
+			{
+				Name:      "status",
+				Usage:     "Report the current status of builds",
+				ArgsUsage: "[<profile>:]<org>/<repo>/<number>...",
+				Action:    status,
+			},
+			{
+				Name:      "cancel",
+				Usage:     "Cancel builds that have not completed",
+				ArgsUsage: "[<profile>:]<org>/<repo>/<number>...",
+				Action:    cancel,
+			},
+			{
+				Name:   "doctor",
+				Usage:  "Diagnose the Vela server, token, user and repo access for the configuration",
+				Action: doctor,
+			},
+		},
 	}
 
 	// Plugin Flags
@@ -62,6 +93,27 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 				cli.File("/vela/secrets/downstream/log_level"),
 			),
 		},
+		&cli.StringFlag{
+			Name:  "log.format",
+			Usage: "set log format - options: (text|json|logfmt)",
+			Value: "text",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_LOG_FORMAT"),
+				cli.EnvVar("DOWNSTREAM_LOG_FORMAT"),
+				cli.File("/vela/parameters/downstream/log_format"),
+				cli.File("/vela/secrets/downstream/log_format"),
+			),
+		},
+		&cli.StringSliceFlag{
+			Name:  "log.redact",
+			Usage: "list of secret values to mask from logs, errors and files",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_REDACT"),
+				cli.EnvVar("DOWNSTREAM_REDACT"),
+				cli.File("/vela/parameters/downstream/redact"),
+				cli.File("/vela/secrets/downstream/redact"),
+			),
+		},
 
 		// Build Flags
 
@@ -75,9 +127,30 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 				cli.File("/vela/secrets/downstream/branch"),
 			),
 		},
+		&cli.StringFlag{
+			Name:  "build.branch-order",
+			Usage: "build to restart for a branch pattern (newest or semver)",
+			Value: downstream.BranchOrderNewest,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_BRANCH_ORDER"),
+				cli.EnvVar("DOWNSTREAM_BRANCH_ORDER"),
+				cli.File("/vela/parameters/downstream/branch_order"),
+				cli.File("/vela/secrets/downstream/branch_order"),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "build.default-branch",
+			Usage: "determine whether the downstream plugin should use the default branch of repos without a branch",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_DEFAULT_BRANCH"),
+				cli.EnvVar("DOWNSTREAM_DEFAULT_BRANCH"),
+				cli.File("/vela/parameters/downstream/default_branch"),
+				cli.File("/vela/secrets/downstream/default_branch"),
+			),
+		},
 		&cli.StringFlag{
 			Name:  "build.event",
-			Usage: "event to trigger a build for the repo",
+			Usage: "event to trigger a build for the repo, optionally with an action - i.e. (pull_request:opened)",
 			Value: constants.EventPush,
 			Sources: cli.NewValueSourceChain(
 				cli.EnvVar("PARAMETER_EVENT"),
@@ -88,7 +161,7 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 		},
 		&cli.StringSliceFlag{
 			Name:  "build.status",
-			Usage: "list of statuses to trigger a build for the repo",
+			Usage: "list of statuses to trigger a build for the repo - options: (status|!status|any|completed|terminal|non-success)",
 			Value: []string{constants.StatusSuccess},
 			Sources: cli.NewValueSourceChain(
 				cli.EnvVar("PARAMETER_STATUS"),
@@ -97,6 +170,93 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 				cli.File("/vela/secrets/downstream/status"),
 			),
 		},
+		&cli.TimestampFlag{
+			Name:  "build.created-after",
+			Usage: "trigger a build created after the timestamp",
+			Config: cli.TimestampConfig{
+				Layouts: []string{time.RFC3339, time.DateOnly},
+			},
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_CREATED_AFTER"),
+				cli.EnvVar("DOWNSTREAM_CREATED_AFTER"),
+				cli.File("/vela/parameters/downstream/created_after"),
+				cli.File("/vela/secrets/downstream/created_after"),
+			),
+		},
+		&cli.TimestampFlag{
+			Name:  "build.created-before",
+			Usage: "trigger a build created before the timestamp",
+			Config: cli.TimestampConfig{
+				Layouts: []string{time.RFC3339, time.DateOnly},
+			},
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_CREATED_BEFORE"),
+				cli.EnvVar("DOWNSTREAM_CREATED_BEFORE"),
+				cli.File("/vela/parameters/downstream/created_before"),
+				cli.File("/vela/secrets/downstream/created_before"),
+			),
+		},
+		&cli.DurationFlag{
+			Name:  "build.max-age",
+			Usage: "trigger a build created within the max age",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_MAX_AGE"),
+				cli.EnvVar("DOWNSTREAM_MAX_AGE"),
+				cli.File("/vela/parameters/downstream/max_age"),
+				cli.File("/vela/secrets/downstream/max_age"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "build.author",
+			Usage: "trigger a build for a commit from the author",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_AUTHOR"),
+				cli.EnvVar("DOWNSTREAM_AUTHOR"),
+				cli.File("/vela/parameters/downstream/author"),
+				cli.File("/vela/secrets/downstream/author"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "build.sender",
+			Usage: "trigger a build triggered by the sender",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_SENDER"),
+				cli.EnvVar("DOWNSTREAM_SENDER"),
+				cli.File("/vela/parameters/downstream/sender"),
+				cli.File("/vela/secrets/downstream/sender"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "build.message",
+			Usage: "trigger a build for a commit message matching the regular expression",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_MESSAGE"),
+				cli.EnvVar("DOWNSTREAM_MESSAGE"),
+				cli.File("/vela/parameters/downstream/message"),
+				cli.File("/vela/secrets/downstream/message"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "build.order",
+			Usage: "build to restart from the builds matching the filters (newest or oldest)",
+			Value: downstream.OrderNewest,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_ORDER"),
+				cli.EnvVar("DOWNSTREAM_ORDER"),
+				cli.File("/vela/parameters/downstream/order"),
+				cli.File("/vela/secrets/downstream/order"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "build.schedule",
+			Usage: "name of the schedule to restart the latest build of for the repo",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_SCHEDULE"),
+				cli.EnvVar("DOWNSTREAM_SCHEDULE"),
+				cli.File("/vela/parameters/downstream/schedule"),
+				cli.File("/vela/secrets/downstream/schedule"),
+			),
+		},
 		&cli.BoolFlag{
 			Name:  "build.continue",
 			Usage: "determine whether the downstream plugin should continue through repo list if a build is not found to restart",
@@ -108,6 +268,29 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 			),
 		},
 
+		&cli.BoolFlag{
+			Name:  "build.preflight",
+			Usage: "determine whether the downstream plugin should verify the token user and every repo before restarting any builds",
+			Value: true,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_PREFLIGHT"),
+				cli.EnvVar("DOWNSTREAM_PREFLIGHT"),
+				cli.File("/vela/parameters/downstream/preflight"),
+				cli.File("/vela/secrets/downstream/preflight"),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "build.loop-detection",
+			Usage: "determine whether the downstream plugin should refuse to trigger the upstream repo and branch of the upstream build",
+			Value: true,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_LOOP_DETECTION"),
+				cli.EnvVar("DOWNSTREAM_LOOP_DETECTION"),
+				cli.File("/vela/parameters/downstream/loop_detection"),
+				cli.File("/vela/secrets/downstream/loop_detection"),
+			),
+		},
+
 		// Build Check Flags
 
 		&cli.BoolFlag{
@@ -133,7 +316,7 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 		},
 		&cli.StringSliceFlag{
 			Name:  "build-check.status",
-			Usage: "list of statuses that constitute a successful triggered build",
+			Usage: "list of statuses that constitute a successful triggered build - options: (status|!status|any|completed|terminal|non-success)",
 			Value: []string{constants.StatusSuccess},
 			Sources: cli.NewValueSourceChain(
 				cli.EnvVar("PARAMETER_TARGET_STATUS"),
@@ -142,6 +325,48 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 				cli.File("/vela/secrets/downstream/target_status"),
 			),
 		},
+		&cli.BoolFlag{
+			Name:  "build-check.approve",
+			Usage: "determine whether the downstream plugin should approve triggered builds pending approval",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_APPROVE"),
+				cli.EnvVar("DOWNSTREAM_APPROVE"),
+				cli.File("/vela/parameters/downstream/approve"),
+				cli.File("/vela/secrets/downstream/approve"),
+			),
+		},
+		&cli.IntFlag{
+			Name:  "build-check.concurrency",
+			Usage: "max number of repos to check the status of triggered builds for at the same time",
+			Value: 10,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_CONCURRENCY"),
+				cli.EnvVar("DOWNSTREAM_CONCURRENCY"),
+				cli.File("/vela/parameters/downstream/concurrency"),
+				cli.File("/vela/secrets/downstream/concurrency"),
+			),
+		},
+		&cli.IntFlag{
+			Name:  "build-check.retries",
+			Usage: "max number of times to restart a triggered build that completed with a retry status",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_RETRIES"),
+				cli.EnvVar("DOWNSTREAM_RETRIES"),
+				cli.File("/vela/parameters/downstream/retries"),
+				cli.File("/vela/secrets/downstream/retries"),
+			),
+		},
+		&cli.StringSliceFlag{
+			Name:  "build-check.retry-status",
+			Usage: "list of statuses of triggered builds to restart - options: (failure|error|killed)",
+			Value: []string{constants.StatusFailure, constants.StatusError, constants.StatusKilled},
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_RETRY_STATUS"),
+				cli.EnvVar("DOWNSTREAM_RETRY_STATUS"),
+				cli.File("/vela/parameters/downstream/retry_status"),
+				cli.File("/vela/secrets/downstream/retry_status"),
+			),
+		},
 
 		// Config Flags
 
@@ -157,7 +382,7 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 		},
 		&cli.StringFlag{
 			Name:  "config.token",
-			Usage: "user token to authenticate with the Vela server",
+			Usage: "token to authenticate with the Vela server",
 			Sources: cli.NewValueSourceChain(
 				cli.EnvVar("PARAMETER_TOKEN"),
 				cli.EnvVar("DOWNSTREAM_TOKEN"),
@@ -165,6 +390,97 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 				cli.File("/vela/secrets/downstream/token"),
 			),
 		},
+		&cli.StringFlag{
+			Name:  "config.auth-method",
+			Usage: "method to authenticate with the Vela server - options: (pat|token|refresh)",
+			Value: downstream.AuthPersonalAccessToken,
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_AUTH_METHOD"),
+				cli.EnvVar("DOWNSTREAM_AUTH_METHOD"),
+				cli.File("/vela/parameters/downstream/auth_method"),
+				cli.File("/vela/secrets/downstream/auth_method"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "config.refresh-token",
+			Usage: "refresh token to exchange for access tokens from the Vela server",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_REFRESH_TOKEN"),
+				cli.EnvVar("DOWNSTREAM_REFRESH_TOKEN"),
+				cli.File("/vela/parameters/downstream/refresh_token"),
+				cli.File("/vela/secrets/downstream/refresh_token"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "config.ca-cert",
+			Usage: "path to a CA bundle for verifying the Vela server certificate",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_CA_CERT"),
+				cli.EnvVar("DOWNSTREAM_CA_CERT"),
+				cli.File("/vela/parameters/downstream/ca_cert"),
+				cli.File("/vela/secrets/downstream/ca_cert"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "config.client-cert",
+			Usage: "path to a client certificate for authenticating with the Vela server",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_CLIENT_CERT"),
+				cli.EnvVar("DOWNSTREAM_CLIENT_CERT"),
+				cli.File("/vela/parameters/downstream/client_cert"),
+				cli.File("/vela/secrets/downstream/client_cert"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "config.client-key",
+			Usage: "path to a client key for authenticating with the Vela server",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_CLIENT_KEY"),
+				cli.EnvVar("DOWNSTREAM_CLIENT_KEY"),
+				cli.File("/vela/parameters/downstream/client_key"),
+				cli.File("/vela/secrets/downstream/client_key"),
+			),
+		},
+		&cli.BoolFlag{
+			Name:  "config.insecure-skip-verify",
+			Usage: "skip verifying the Vela server certificate (NOT recommended)",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_INSECURE_SKIP_VERIFY"),
+				cli.EnvVar("DOWNSTREAM_INSECURE_SKIP_VERIFY"),
+				cli.File("/vela/parameters/downstream/insecure_skip_verify"),
+				cli.File("/vela/secrets/downstream/insecure_skip_verify"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "config.proxy",
+			Usage: "HTTP(S) proxy to reach the Vela server through",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_PROXY"),
+				cli.EnvVar("DOWNSTREAM_PROXY"),
+				cli.File("/vela/parameters/downstream/proxy"),
+				cli.File("/vela/secrets/downstream/proxy"),
+			),
+		},
+		&cli.DurationFlag{
+			Name:  "config.request-timeout",
+			Usage: "timeout for each request to the Vela server",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_REQUEST_TIMEOUT"),
+				cli.EnvVar("DOWNSTREAM_REQUEST_TIMEOUT"),
+				cli.File("/vela/parameters/downstream/request_timeout"),
+				cli.File("/vela/secrets/downstream/request_timeout"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "config.profiles",
+			Usage: "JSON object of named profiles of Vela servers and credentials",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_PROFILES"),
+				cli.EnvVar("DOWNSTREAM_PROFILES"),
+				cli.File("/vela/parameters/downstream/profiles"),
+				cli.File("/vela/secrets/downstream/profiles"),
+			),
+		},
 		&cli.IntFlag{
 			Name:  "config.depth",
 			Usage: "number of builds to search for downstream repositories",
@@ -177,11 +493,74 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 			),
 		},
 
+		// Builds Flags
+
+		&cli.StringSliceFlag{
+			Name:  "builds.names",
+			Usage: "list of [<profile>:]<org>/<repo>/<number> builds for the wait, status and cancel commands",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_BUILDS"),
+				cli.EnvVar("DOWNSTREAM_BUILDS"),
+				cli.File("/vela/parameters/downstream/builds"),
+				cli.File("/vela/secrets/downstream/builds"),
+			),
+		},
+		&cli.StringFlag{
+			Name:  "builds.file",
+			Usage: "file the trigger command writes the triggered builds to and the wait, status and cancel commands read builds from",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_BUILDS_FILE"),
+				cli.EnvVar("DOWNSTREAM_BUILDS_FILE"),
+				cli.File("/vela/parameters/downstream/builds_file"),
+				cli.File("/vela/secrets/downstream/builds_file"),
+			),
+		},
+
+		// State Flags
+
+		&cli.StringFlag{
+			Name:  "state.file",
+			Usage: "file to record triggered builds to for resuming them when the step is retried",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_STATE_FILE"),
+				cli.EnvVar("DOWNSTREAM_STATE_FILE"),
+				cli.File("/vela/parameters/downstream/state_file"),
+				cli.File("/vela/secrets/downstream/state_file"),
+			),
+		},
+
+		// Upstream Flags
+
+		&cli.StringFlag{
+			Name:    "upstream.repo",
+			Usage:   "full name of the upstream repo running the plugin",
+			Sources: cli.EnvVars("VELA_REPO_FULL_NAME"),
+		},
+		&cli.StringFlag{
+			Name:    "upstream.build",
+			Usage:   "number of the upstream build running the plugin",
+			Sources: cli.EnvVars("VELA_BUILD_NUMBER"),
+		},
+
+		// Command Flags
+
+		&cli.StringFlag{
+			Name:  "command",
+			Usage: "command to run when none is provided - options: (trigger|wait|status|cancel|doctor)",
+			Value: "trigger",
+			Sources: cli.NewValueSourceChain(
+				cli.EnvVar("PARAMETER_COMMAND"),
+				cli.EnvVar("DOWNSTREAM_COMMAND"),
+				cli.File("/vela/parameters/downstream/command"),
+				cli.File("/vela/secrets/downstream/command"),
+			),
+		},
+
 		// Repo Flags
 
 		&cli.StringSliceFlag{
 			Name:  "repo.names",
-			Usage: "list of <org>/<repo> names to trigger",
+			Usage: "list of [<profile>:]<org>/<repo>[@<branch>|#<number>] names to trigger",
 			Sources: cli.NewValueSourceChain(
 				cli.EnvVar("PARAMETER_REPOS"),
 				cli.EnvVar("DOWNSTREAM_REPOS"),
@@ -193,12 +572,53 @@ func main() { //nolint: funlen // length of main function is acceptable for CLI
 
 	err = cmd.Run(context.Background(), os.Args)
 	if err != nil {
-		logrus.Fatal(err)
+		logrus.Error(err)
+
+		// exit with the code for the category of the error
+		os.Exit(exitCode(err))
+	}
+}
+
+// run executes the plugin command selected by the configuration provided.
+//
+// This enables selecting a command for the plugin with a parameter,
+// since a pipeline step for a plugin cannot provide arguments.
+func run(ctx context.Context, c *cli.Command) error {
+	switch c.String("command") {
+	case "wait":
+		return wait(ctx, c)
+	case "status":
+		return status(ctx, c)
+	case "cancel":
+		return cancel(ctx, c)
+	case "doctor":
+		return doctor(ctx, c)
+	case "trigger":
+		return trigger(ctx, c)
+	default:
+		return fmt.Errorf("%w: invalid command provided: %s", downstream.ErrConfig, c.String("command"))
+	}
+}
+
+// doctor diagnoses the plugin based off the configuration provided.
+func doctor(ctx context.Context, c *cli.Command) error {
+	// create the plugin
+	p, redactor, err := setup(c)
+	if err != nil {
+		return err
 	}
+
+	// create the output with secret values masked
+	out := redactor.Writer(os.Stdout)
+	defer out.Close()
+
+	// diagnose the plugin
+	return redactor.Error(p.Doctor(ctx, out))
 }
 
-// run executes the plugin based off the configuration provided.
-func run(_ context.Context, c *cli.Command) error {
+// setup configures the logging and creates the plugin
+// based off the configuration provided.
+func setup(c *cli.Command) (*downstream.Plugin, *downstream.Redactor, error) {
 	// set the log level for the plugin
 	switch c.String("log.level") {
 	case "t", "trace", "Trace", "TRACE":
@@ -219,44 +639,113 @@ func run(_ context.Context, c *cli.Command) error {
 		logrus.SetLevel(logrus.InfoLevel)
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"code":     "https://github.com/go-vela/vela-downstream",
-		"docs":     "https://go-vela.github.io/docs/plugins/registry/pipeline/downstream",
-		"registry": "https://hub.docker.com/r/target/vela-downstream",
-	}).Info("Vela Downstream Plugin")
+	// parse the profiles for the plugin
+	profiles := make(map[string]*downstream.Profile)
 
-	// create the plugin
-	p := &Plugin{
+	if len(c.String("config.profiles")) > 0 {
+		err := json.Unmarshal([]byte(c.String("config.profiles")), &profiles)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: unable to parse config profiles: %w", downstream.ErrConfig, err)
+		}
+	}
+
+	// capture the upstream build running the plugin
+	upstream := ""
+
+	if len(c.String("upstream.repo")) > 0 && len(c.String("upstream.build")) > 0 {
+		upstream = fmt.Sprintf("%s/%s", c.String("upstream.repo"), c.String("upstream.build"))
+	}
+
+	// create the options for the plugin
+	opts := []downstream.Option{
 		// build configuration
-		Build: &Build{
-			Branch:       c.String("build.branch"),
-			Event:        c.String("build.event"),
-			Status:       c.StringSlice("build.status"),
-			Report:       c.Bool("build-check.enabled"),
-			TargetStatus: c.StringSlice("build-check.status"),
-			Timeout:      c.Duration("build-check.timeout"),
-			Continue:     c.Bool("build.continue"),
-		},
+		downstream.WithBuild(&downstream.Build{
+			Branch:        c.String("build.branch"),
+			BranchOrder:   c.String("build.branch-order"),
+			DefaultBranch: c.Bool("build.default-branch"),
+			Event:         c.String("build.event"),
+			Status:        c.StringSlice("build.status"),
+			CreatedAfter:  c.Timestamp("build.created-after"),
+			CreatedBefore: c.Timestamp("build.created-before"),
+			MaxAge:        c.Duration("build.max-age"),
+			Author:        c.String("build.author"),
+			Sender:        c.String("build.sender"),
+			Message:       c.String("build.message"),
+			Order:         c.String("build.order"),
+			Schedule:      c.String("build.schedule"),
+			Report:        c.Bool("build-check.enabled"),
+			TargetStatus:  c.StringSlice("build-check.status"),
+			Timeout:       c.Duration("build-check.timeout"),
+			Concurrency:   c.Int("build-check.concurrency"),
+			Approve:       c.Bool("build-check.approve"),
+			Retries:       c.Int("build-check.retries"),
+			RetryStatus:   c.StringSlice("build-check.retry-status"),
+			Continue:      c.Bool("build.continue"),
+			Preflight:     c.Bool("build.preflight"),
+			Upstream:      upstream,
+			DetectLoops:   c.Bool("build.loop-detection"),
+		}),
 		// config configuration
-		Config: &Config{
-			Server:     c.String("config.server"),
-			Token:      c.String("config.token"),
-			Depth:      c.Int("config.depth"),
-			AppName:    c.Name,
-			AppVersion: c.Version,
-		},
+		downstream.WithConfig(&downstream.Config{
+			Server:             c.String("config.server"),
+			AuthMethod:         c.String("config.auth-method"),
+			Token:              c.String("config.token"),
+			RefreshToken:       c.String("config.refresh-token"),
+			CACert:             c.String("config.ca-cert"),
+			ClientCert:         c.String("config.client-cert"),
+			ClientKey:          c.String("config.client-key"),
+			InsecureSkipVerify: c.Bool("config.insecure-skip-verify"),
+			Proxy:              c.String("config.proxy"),
+			Timeout:            c.Duration("config.request-timeout"),
+			Profiles:           profiles,
+			Depth:              c.Int("config.depth"),
+			AppName:            c.Name,
+			AppVersion:         c.Version,
+		}),
 		// repo configuration
-		Repo: &Repo{
-			Names: c.StringSlice("repo.names"),
-		},
+		downstream.WithRepos(c.StringSlice("repo.names")...),
 	}
 
-	// validate the plugin
-	err := p.Validate()
+	// check if a state file is provided
+	if len(c.String("state.file")) > 0 {
+		// state configuration
+		opts = append(opts, downstream.WithState(c.String("state.file"), upstream))
+	}
+
+	// create the plugin
+	p, err := downstream.New(opts...)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	// execute the plugin
-	return p.Exec()
+	// create the redactor to mask secrets from the plugin
+	redactor := downstream.NewRedactor(append(p.Config.Secrets(), c.StringSlice("log.redact")...)...)
+
+	// set the log format for the plugin
+	var formatter logrus.Formatter
+
+	switch c.String("log.format") {
+	case "j", "json", "Json", "JSON":
+		formatter = &logrus.JSONFormatter{}
+	case "l", "logfmt", "Logfmt", "LOGFMT":
+		formatter = &logrus.TextFormatter{
+			DisableColors: true,
+			FullTimestamp: true,
+		}
+	case "t", "text", "Text", "TEXT":
+		fallthrough
+	default:
+		formatter = &logrus.TextFormatter{}
+	}
+
+	// mask secrets from every log entry for the plugin
+	logrus.SetFormatter(redactor.Formatter(formatter))
+
+	logrus.WithFields(logrus.Fields{
+		"code":     "https://github.com/go-vela/vela-downstream",
+		"docs":     "https://go-vela.github.io/docs/plugins/registry/pipeline/downstream",
+		"registry": "https://hub.docker.com/r/target/vela-downstream",
+	}).Info("Vela Downstream Plugin")
+
+	return p, redactor, nil
 }
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	// BranchOrderNewest defines the order selecting the
+	// newest build from all branches matching a pattern.
+	BranchOrderNewest = "newest"
+
+	// BranchOrderSemver defines the order selecting the newest build
+	// from the branch matching a pattern with the highest version.
+	BranchOrderSemver = "semver"
+)
+
+// branchMatcher returns a function reporting whether a branch matches
+// the provided branch pattern, or nil when the branch is a literal.
+//
+// A branch wrapped in slashes is a regular expression, i.e. /^release-\d+$/,
+// and a branch with any of *?[ is a glob pattern, i.e. release/*.
+func branchMatcher(branch string) (func(string) bool, error) {
+	switch {
+	case len(branch) > 2 && strings.HasPrefix(branch, "/") && strings.HasSuffix(branch, "/"):
+		re, err := regexp.Compile(branch[1 : len(branch)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid branch regular expression %s: %w", branch, err)
+		}
+
+		return re.MatchString, nil
+	case strings.ContainsAny(branch, "*?["):
+		// verify the glob pattern is valid
+		_, err := path.Match(branch, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid branch pattern %s: %w", branch, err)
+		}
+
+		return func(b string) bool {
+			ok, _ := path.Match(branch, b)
+
+			return ok
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// branchVersion returns the semantic version from the provided branch,
+// i.e. 1.2.3 from release/v1.2.3, or nil when it has no version.
+func branchVersion(branch string) *semver.Version {
+	// capture the version from the last segment of the branch
+	v, err := semver.NewVersion(branch[strings.LastIndex(branch, "/")+1:])
+	if err != nil {
+		return nil
+	}
+
+	return v
+}
+
+// higherBranch returns whether the provided branch has a higher
+// semantic version than the current branch. A branch with a version
+// is higher than a branch without one.
+func higherBranch(branch, current string) bool {
+	v := branchVersion(branch)
+	if v == nil {
+		return false
+	}
+
+	c := branchVersion(current)
+	if c == nil {
+		return true
+	}
+
+	return v.GreaterThan(c)
+}
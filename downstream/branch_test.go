@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"testing"
+)
+
+func TestDownstream_branchMatcher(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		pattern string
+		literal bool
+		matches []string
+		misses  []string
+	}{
+		{
+			pattern: "main",
+			literal: true,
+		},
+		{
+			pattern: "release/*",
+			matches: []string{"release/1.2.3", "release/next"},
+			misses:  []string{"main", "release/1.2/hotfix", "feature/release/1"},
+		},
+		{
+			pattern: "v1.?",
+			matches: []string{"v1.2", "v1.9"},
+			misses:  []string{"v1.10", "v2.0"},
+		},
+		{
+			pattern: `/^release-\d+$/`,
+			matches: []string{"release-1", "release-42"},
+			misses:  []string{"release-next", "my-release-1"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			got, err := branchMatcher(test.pattern)
+			if err != nil {
+				t.Errorf("branchMatcher returned err: %v", err)
+			}
+
+			if test.literal {
+				if got != nil {
+					t.Errorf("branchMatcher should have returned nil for literal branch")
+				}
+
+				return
+			}
+
+			for _, branch := range test.matches {
+				if !got(branch) {
+					t.Errorf("branchMatcher %s should have matched %s", test.pattern, branch)
+				}
+			}
+
+			for _, branch := range test.misses {
+				if got(branch) {
+					t.Errorf("branchMatcher %s should not have matched %s", test.pattern, branch)
+				}
+			}
+		})
+	}
+}
+
+func TestDownstream_branchMatcher_Invalid(t *testing.T) {
+	// setup tests
+	tests := []string{
+		"release/[",
+		"/release-(/",
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			_, err := branchMatcher(test)
+			if err == nil {
+				t.Errorf("branchMatcher should have returned err")
+			}
+		})
+	}
+}
+
+func TestDownstream_higherBranch(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		branch  string
+		current string
+		want    bool
+	}{
+		{branch: "release/1.10.0", current: "release/1.9.0", want: true},
+		{branch: "release/v2", current: "release/1.9.0", want: true},
+		{branch: "release/1.9.0", current: "release/1.10.0", want: false},
+		{branch: "release/1.9.0", current: "release/next", want: true},
+		{branch: "release/next", current: "release/1.9.0", want: false},
+		{branch: "release/1.9.0", current: "release/1.9.0", want: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.branch+">"+test.current, func(t *testing.T) {
+			got := higherBranch(test.branch, test.current)
+			if got != test.want {
+				t.Errorf("higherBranch is %t, want %t", got, test.want)
+			}
+		})
+	}
+}
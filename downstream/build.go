@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/server/constants"
+)
+
+// failedStatuses represents the statuses of a build
+// that completed without succeeding to retry.
+var failedStatuses = []string{
+	constants.StatusError,
+	constants.StatusFailure,
+	constants.StatusKilled,
+}
+
+// Build represents the plugin configuration for Build information.
+type Build struct {
+	// branch to trigger a build for the repo
+	Branch string
+	// resolve the default branch for repos without a branch
+	DefaultBranch bool
+	// order for selecting a build from branches matching a pattern
+	BranchOrder string
+	// event to trigger a build for the repo,
+	// optionally with an action, i.e. pull_request:opened
+	Event string
+	// status to trigger a build for the repo, optionally
+	// negated or a group of statuses, i.e. !canceled
+	Status []string
+	// trigger a build created after the timestamp
+	CreatedAfter time.Time
+	// trigger a build created before the timestamp
+	CreatedBefore time.Time
+	// trigger a build created within the max age
+	MaxAge time.Duration
+	// trigger a build for a commit from the author
+	Author string
+	// trigger a build triggered by the sender
+	Sender string
+	// trigger a build for a commit message matching the expression
+	Message string
+	// order for selecting a build matching the filters
+	Order string
+	// name of the schedule to trigger a build for
+	Schedule string
+	// report determines whether to report back the build statuses
+	Report bool
+	// target status for triggered builds
+	TargetStatus []string
+	// timeout for waiting on triggered builds
+	Timeout time.Duration
+	// max number of repos to check triggered builds for at the same time
+	Concurrency int
+	// approve triggered builds pending approval
+	Approve bool
+	// max number of times to restart a failed triggered build
+	Retries int
+	// statuses of triggered builds to restart
+	RetryStatus []string
+	// continue through repo list if build is not found to restart
+	Continue bool
+	// verify the user and repos before restarting any builds
+	Preflight bool
+	// upstream build running the plugin, i.e. <org>/<repo>/<number>
+	Upstream string
+	// refuse to trigger the upstream repo and branch of the upstream build
+	DetectLoops bool
+}
+
+// Validate verifies the Build is properly configured.
+func (b *Build) Validate() error {
+	logrus.Trace("validating build configuration")
+
+	// verify build branch is provided
+	if len(b.Branch) == 0 {
+		logrus.Debug("no build branch provided for filtering")
+	}
+
+	// verify build event is provided
+	if len(b.Event) == 0 {
+		return fmt.Errorf("no build event provided")
+	}
+
+	// set timeout
+	if b.Timeout > (90 * time.Minute) {
+		logrus.Info("timeout set too high. Using 90 minutes...")
+
+		b.Timeout = 90 * time.Minute
+	}
+
+	// create a list of valid events for a build
+	validEvents := []string{
+		constants.EventComment,
+		constants.EventDelete,
+		constants.EventDeploy,
+		constants.EventPull,
+		constants.EventPush,
+		constants.EventSchedule,
+		constants.EventTag,
+	}
+
+	// create a list of valid actions by event for a build
+	validActions := map[string][]string{
+		constants.EventComment: {
+			constants.ActionCreated,
+			constants.ActionEdited,
+		},
+		constants.EventDelete: {
+			constants.ActionBranch,
+			constants.ActionTag,
+		},
+		constants.EventDeploy: {
+			constants.ActionCreated,
+		},
+		constants.EventPull: {
+			constants.ActionEdited,
+			constants.ActionLabeled,
+			constants.ActionOpened,
+			constants.ActionReopened,
+			constants.ActionSynchronize,
+			constants.ActionUnlabeled,
+		},
+		constants.EventSchedule: {
+			constants.ActionRun,
+		},
+	}
+
+	event, action := b.event()
+
+	// verify the build event provided is valid
+	if !contains(validEvents, event) {
+		return fmt.Errorf("invalid build event provided: %s", event)
+	}
+
+	// verify the build event action provided is valid
+	if strings.Contains(b.Event, ":") && !contains(validActions[event], action) {
+		return fmt.Errorf("invalid build event action provided for %s event: %q", event, action)
+	}
+
+	// verify build status is provided
+	if len(b.Status) == 0 {
+		return fmt.Errorf("no build status provided")
+	}
+
+	// verify the build statuses provided are valid
+	err := validateStatuses(b.Status)
+	if err != nil {
+		return err
+	}
+
+	// verify the target statuses provided are valid
+	err = validateStatuses(b.TargetStatus)
+	if err != nil {
+		return fmt.Errorf("invalid build check status: %w", err)
+	}
+
+	// verify the branch pattern provided is valid
+	_, err = branchMatcher(b.Branch)
+	if err != nil {
+		return err
+	}
+
+	// verify the branch order provided is valid
+	switch b.BranchOrder {
+	case "", BranchOrderNewest, BranchOrderSemver:
+	default:
+		return fmt.Errorf("invalid branch order provided: %s", b.BranchOrder)
+	}
+
+	// verify the build order provided is valid
+	switch b.Order {
+	case "", OrderNewest, OrderOldest:
+	default:
+		return fmt.Errorf("invalid build order provided: %s", b.Order)
+	}
+
+	// verify the build max age provided is valid
+	if b.MaxAge < 0 {
+		return fmt.Errorf("invalid build max age provided: %s", b.MaxAge)
+	}
+
+	// verify the build time window provided is valid
+	if !b.CreatedAfter.IsZero() && !b.CreatedBefore.IsZero() && !b.CreatedAfter.Before(b.CreatedBefore) {
+		return fmt.Errorf("invalid build time window provided: created after %s is not before %s",
+			b.CreatedAfter.Format(time.RFC3339), b.CreatedBefore.Format(time.RFC3339))
+	}
+
+	// verify the build message expression provided is valid
+	_, err = b.filter(time.Now())
+	if err != nil {
+		return err
+	}
+
+	// verify build concurrency provided is valid
+	if b.Concurrency < 0 {
+		return fmt.Errorf("invalid build concurrency provided: %d", b.Concurrency)
+	}
+
+	// verify build retries provided is valid
+	if b.Retries < 0 {
+		return fmt.Errorf("invalid build retries provided: %d", b.Retries)
+	}
+
+	// iterate through the retry statuses provided
+	for _, status := range b.RetryStatus {
+		// verify the retry status provided is a failed status
+		if !contains(failedStatuses, status) {
+			return fmt.Errorf("invalid build retry status provided: %s", status)
+		}
+	}
+
+	// verify the upstream build provided is valid
+	if len(b.Upstream) > 0 {
+		_, err := ParseTriggered(b.Upstream)
+		if err != nil {
+			return fmt.Errorf("invalid upstream build provided: %s", b.Upstream)
+		}
+	}
+
+	return nil
+}
+
+// event returns the event and the optional event action
+// of the build, i.e. pull_request and opened.
+func (b *Build) event() (string, string) {
+	event, action, _ := strings.Cut(b.Event, ":")
+
+	return event, action
+}
+
+// contains checks if the provided input string is found in the given list of
+// strings. If the input string is not found, then the function returns false.
+func contains(list []string, input string) bool {
+	// iterate through the list of strings
+	for _, item := range list {
+		// check if the item matches the input
+		if strings.EqualFold(item, input) {
+			return true
+		}
+	}
+
+	return false
+}
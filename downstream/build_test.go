@@ -0,0 +1,314 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-vela/server/constants"
+)
+
+func TestDownstream_Build_Validate(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch: "main",
+		Event:  constants.EventPush,
+		Status: []string{constants.StatusSuccess},
+	}
+
+	// run test
+	err := b.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+}
+
+func TestDownstream_Build_Validate_NoBranch(t *testing.T) {
+	// setup types
+	b := &Build{
+		Event:  constants.EventPush,
+		Status: []string{constants.StatusSuccess},
+	}
+
+	// run test
+	err := b.Validate()
+	if err != nil {
+		t.Errorf("Validate should not have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_NoEvent(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch: "main",
+		Status: []string{constants.StatusSuccess},
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_NoStatus(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch: "main",
+		Event:  constants.EventPush,
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidEvent(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch: "main",
+		Event:  "foo",
+		Status: []string{constants.StatusSuccess},
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidStatus(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch: "main",
+		Event:  constants.EventPush,
+		Status: []string{"foo"},
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_HighTimeout(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch:  "main",
+		Event:   constants.EventPush,
+		Status:  []string{"success"},
+		Timeout: 120 * time.Minute,
+	}
+
+	err := b.Validate()
+	if err != nil {
+		t.Errorf("Validate returned an error")
+	}
+
+	if b.Timeout != (90 * time.Minute) {
+		t.Errorf("Validate should have a timeout of max 90 minutes")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidRetries(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch:  "main",
+		Event:   constants.EventPush,
+		Status:  []string{constants.StatusSuccess},
+		Retries: -1,
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidRetryStatus(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch:      "main",
+		Event:       constants.EventPush,
+		Status:      []string{constants.StatusSuccess},
+		Retries:     1,
+		RetryStatus: []string{constants.StatusSuccess},
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidBranchPattern(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch: "release/[",
+		Event:  constants.EventPush,
+		Status: []string{constants.StatusSuccess},
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidBranchOrder(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch:      "release/*",
+		BranchOrder: "oldest",
+		Event:       constants.EventPush,
+		Status:      []string{constants.StatusSuccess},
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidOrder(t *testing.T) {
+	// setup types
+	b := &Build{
+		Event:  constants.EventPush,
+		Status: []string{constants.StatusSuccess},
+		Order:  "random",
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidTimeWindow(t *testing.T) {
+	// setup types
+	b := &Build{
+		Event:         constants.EventPush,
+		Status:        []string{constants.StatusSuccess},
+		CreatedAfter:  time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC),
+		CreatedBefore: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidMaxAge(t *testing.T) {
+	// setup types
+	b := &Build{
+		Event:  constants.EventPush,
+		Status: []string{constants.StatusSuccess},
+		MaxAge: -time.Hour,
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidMessage(t *testing.T) {
+	// setup types
+	b := &Build{
+		Event:   constants.EventPush,
+		Status:  []string{constants.StatusSuccess},
+		Message: "release-(",
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_InvalidConcurrency(t *testing.T) {
+	// setup types
+	b := &Build{
+		Event:       constants.EventPush,
+		Status:      []string{constants.StatusSuccess},
+		Concurrency: -1,
+	}
+
+	// run test
+	err := b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Build_Validate_EventAction(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		event   string
+		failure bool
+	}{
+		{event: constants.EventDelete},
+		{event: constants.EventSchedule},
+		{event: "pull_request:opened"},
+		{event: "pull_request:synchronize"},
+		{event: "comment:created"},
+		{event: "deployment:created"},
+		{event: "schedule:run"},
+		{event: "delete:tag"},
+		{event: "pull_request:run", failure: true},
+		{event: "push:opened", failure: true},
+		{event: "comment:", failure: true},
+		{event: "foo:created", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.event, func(t *testing.T) {
+			b := &Build{
+				Event:  test.event,
+				Status: []string{constants.StatusSuccess},
+			}
+
+			err := b.Validate()
+			if test.failure != (err != nil) {
+				t.Errorf("Validate returned err %v, want failure %t", err, test.failure)
+			}
+		})
+	}
+}
+
+func TestDownstream_Build_Validate_StatusFilters(t *testing.T) {
+	// setup types
+	b := &Build{
+		Event:        constants.EventPush,
+		Status:       []string{"completed", "!canceled"},
+		TargetStatus: []string{"!failure"},
+	}
+
+	// run test
+	err := b.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+
+	b.TargetStatus = []string{"foo"}
+
+	err = b.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
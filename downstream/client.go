@@ -0,0 +1,331 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+)
+
+// Client represents the Vela API used for
+// triggering and waiting on downstream builds.
+//
+// Errors returned from a Client should be classified,
+// i.e. wrap ErrNotFound, so callers can react to them.
+type Client interface {
+	// Version returns the version of the Vela server.
+	Version(ctx context.Context) (string, error)
+	// Authenticate verifies the credentials of the client.
+	Authenticate(ctx context.Context) error
+	// GetCurrentUser returns the user the credentials belong to.
+	GetCurrentUser(ctx context.Context) (*api.User, error)
+	// GetRepo returns the provided repo.
+	GetRepo(ctx context.Context, org, repo string) (*api.Repo, error)
+	// ListBuilds returns a page of builds for the provided repo
+	// along with the next page, which is zero on the last page.
+	ListBuilds(ctx context.Context, org, repo string, opts *vela.BuildListOptions) ([]api.Build, int, error)
+	// GetBuild returns the provided build.
+	GetBuild(ctx context.Context, org, repo string, build int64) (*api.Build, error)
+	// RestartBuild restarts the provided build and returns the new build.
+	RestartBuild(ctx context.Context, org, repo string, build int64) (*api.Build, error)
+	// CancelBuild cancels the provided build and returns the canceled build.
+	CancelBuild(ctx context.Context, org, repo string, build int64) (*api.Build, error)
+	// ApproveBuild approves the provided build pending approval.
+	ApproveBuild(ctx context.Context, org, repo string, build int64) error
+	// GetSchedule returns the provided schedule.
+	GetSchedule(ctx context.Context, org, repo, schedule string) (*api.Schedule, error)
+}
+
+// client represents a Client for the Vela API using the Vela SDK.
+type client struct {
+	// Vela client to interact with
+	vela *vela.Client
+	// configuration the Vela client was created from
+	config *Config
+}
+
+// NewClient creates a Client for the Vela API from the provided configuration.
+func NewClient(c *Config) (Client, error) {
+	// create new Vela client from configuration
+	v, err := c.New()
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{vela: v, config: c}, nil
+}
+
+// Version returns the version of the Vela server.
+func (c *client) Version(ctx context.Context) (string, error) {
+	// create HTTP client from configuration
+	httpClient, err := c.config.HTTPClient()
+	if err != nil {
+		return "", err
+	}
+
+	// fall back to a client with the default timeout
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	// send request to capture the version of the server
+	u := strings.TrimSuffix(c.config.Server, "/") + "/version"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", classify("unable to get server version", nil, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("server returned %s for version", resp.Status)
+	}
+
+	// decode the version from the response
+	v := struct {
+		Canonical string `json:"canonical"`
+	}{}
+
+	err = json.NewDecoder(resp.Body).Decode(&v)
+	if err != nil || len(v.Canonical) == 0 {
+		return "", fmt.Errorf("server returned an invalid version, verify the server is a Vela server")
+	}
+
+	return v.Canonical, nil
+}
+
+// Authenticate verifies the credentials of the client.
+func (c *client) Authenticate(ctx context.Context) error {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	switch c.config.AuthMethod {
+	case AuthToken:
+		// verify the token has not expired
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#IsTokenExpired
+		if vela.IsTokenExpired(c.config.Token) {
+			return fmt.Errorf("%w: token is expired or is not a Vela token", ErrUnauthorized)
+		}
+	case AuthRefreshToken:
+		// send API call to exchange the refresh token for an access token
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#AuthenticationService.RefreshAccessToken
+		resp, err := c.vela.Authentication.RefreshAccessToken(c.config.RefreshToken)
+		if err != nil {
+			return classify("unable to refresh access token", resp, err)
+		}
+	default:
+		// send API call to exchange the personal access token for an access token
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#AuthenticationService.AuthenticateWithToken
+		_, resp, err := c.vela.Authentication.AuthenticateWithToken(c.config.Token)
+		if err != nil {
+			return classify("unable to authenticate with token", resp, err)
+		}
+	}
+
+	return nil
+}
+
+// GetCurrentUser returns the user the credentials belong to.
+func (c *client) GetCurrentUser(ctx context.Context) (*api.User, error) {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// send API call to capture the user for the token
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#UserService.GetCurrent
+	user, resp, err := c.vela.User.GetCurrent()
+	if err != nil {
+		return nil, classify("unable to get user for token", resp, err)
+	}
+
+	return user, nil
+}
+
+// GetRepo returns the provided repo.
+func (c *client) GetRepo(ctx context.Context, org, repo string) (*api.Repo, error) {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// send API call to capture the repo
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#RepoService.Get
+	r, resp, err := c.vela.Repo.Get(org, repo)
+	if err != nil {
+		return nil, classify(fmt.Sprintf("unable to get repo %s/%s", org, repo), resp, err)
+	}
+
+	return r, nil
+}
+
+// ListBuilds returns a page of builds for the provided repo.
+func (c *client) ListBuilds(ctx context.Context, org, repo string, opts *vela.BuildListOptions) ([]api.Build, int, error) {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// send API call to capture a list of builds for the repo
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.GetAll
+	builds, resp, err := c.vela.Build.GetAll(org, repo, opts)
+	if err != nil {
+		return nil, 0, classify(fmt.Sprintf("unable to list builds for %s/%s", org, repo), resp, err)
+	}
+
+	return *builds, nextPage(resp), nil
+}
+
+// GetBuild returns the provided build.
+func (c *client) GetBuild(ctx context.Context, org, repo string, build int64) (*api.Build, error) {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// send API call to capture the build
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.Get
+	b, resp, err := c.vela.Build.Get(org, repo, build)
+	if err != nil {
+		return nil, classify(fmt.Sprintf("unable to get build %s/%s/%d", org, repo, build), resp, err)
+	}
+
+	return b, nil
+}
+
+// RestartBuild restarts the provided build and returns the new build.
+func (c *client) RestartBuild(ctx context.Context, org, repo string, build int64) (*api.Build, error) {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// send API call to restart the build
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.Restart
+	b, resp, err := c.vela.Build.Restart(org, repo, build)
+	if err != nil {
+		return nil, classify(fmt.Sprintf("unable to restart build %s/%s/%d", org, repo, build), resp, err)
+	}
+
+	return b, nil
+}
+
+// CancelBuild cancels the provided build and returns the canceled build.
+func (c *client) CancelBuild(ctx context.Context, org, repo string, build int64) (*api.Build, error) {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// send API call to cancel the build
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.Cancel
+	b, resp, err := c.vela.Build.Cancel(org, repo, build)
+	if err != nil {
+		return nil, classify(fmt.Sprintf("unable to cancel build %s/%s/%d", org, repo, build), resp, err)
+	}
+
+	return b, nil
+}
+
+// ApproveBuild approves the provided build pending approval.
+func (c *client) ApproveBuild(ctx context.Context, org, repo string, build int64) error {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	// send API call to approve the build
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.Approve
+	resp, err := c.vela.Build.Approve(org, repo, build)
+	if err != nil {
+		return classify(fmt.Sprintf("unable to approve build %s/%s/%d", org, repo, build), resp, err)
+	}
+
+	return nil
+}
+
+// GetSchedule returns the provided schedule.
+func (c *client) GetSchedule(ctx context.Context, org, repo, schedule string) (*api.Schedule, error) {
+	// verify the context is still active
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	// send API call to capture the schedule
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#ScheduleService.Get
+	sc, resp, err := c.vela.Schedule.Get(org, repo, schedule)
+	if err != nil {
+		return nil, classify(fmt.Sprintf("unable to get schedule %s for repo %s/%s", schedule, org, repo), resp, err)
+	}
+
+	return sc, nil
+}
+
+// nextPage returns the next page from the Link header of the provided response.
+//
+// The Vela SDK does not populate the pagination values of the response
+// so the next page is parsed from the header sent by the server.
+func nextPage(resp *vela.Response) int {
+	// check if the SDK populated the next page
+	if resp.NextPage > 0 {
+		return resp.NextPage
+	}
+
+	// iterate through each link in the header, i.e. <url>; rel="next"
+	for _, link := range strings.Split(resp.Header.Get("Link"), ",") {
+		target, rel, ok := strings.Cut(link, ";")
+		if !ok || strings.TrimSpace(rel) != `rel="next"` {
+			continue
+		}
+
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			continue
+		}
+
+		page, err := strconv.Atoi(u.Query().Get("page"))
+		if err != nil {
+			continue
+		}
+
+		return page
+	}
+
+	return 0
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/vela-downstream/downstream/velatest"
+)
+
+func TestDownstream_client_CancelBuild(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Status: vela.String(constants.StatusRunning)})
+	s.AddBuild("go-vela/hello-world", &api.Build{})
+
+	client, err := NewClient(&Config{
+		Server: s.URL,
+		Token:  "superSecretVelaToken",
+	})
+	if err != nil {
+		t.Errorf("NewClient returned err: %v", err)
+	}
+
+	// run test
+	got, err := client.CancelBuild(context.Background(), "go-vela", "hello-world", 1)
+	if err != nil {
+		t.Errorf("CancelBuild returned err: %v", err)
+	}
+
+	if got.GetStatus() != constants.StatusCanceled {
+		t.Errorf("CancelBuild is %s, want %s", got.GetStatus(), constants.StatusCanceled)
+	}
+
+	_, err = client.CancelBuild(context.Background(), "go-vela", "hello-world", 2)
+	if err == nil || !strings.Contains(err.Error(), "but its status was success") {
+		t.Errorf("CancelBuild returned err %v, want completed build", err)
+	}
+}
+
+func TestDownstream_client_ApproveBuild(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Status: vela.String(constants.StatusPendingApproval)})
+	s.AddBuild("go-vela/hello-world", &api.Build{})
+
+	client, err := NewClient(&Config{
+		Server: s.URL,
+		Token:  "superSecretVelaToken",
+	})
+	if err != nil {
+		t.Errorf("NewClient returned err: %v", err)
+	}
+
+	// run test
+	err = client.ApproveBuild(context.Background(), "go-vela", "hello-world", 1)
+	if err != nil {
+		t.Errorf("ApproveBuild returned err: %v", err)
+	}
+
+	got := s.Build("go-vela/hello-world", 1)
+	if got.GetStatus() != constants.StatusPending || got.GetApprovedBy() != velatest.User {
+		t.Errorf("ApproveBuild is %s by %s, want %s by %s", got.GetStatus(), got.GetApprovedBy(), constants.StatusPending, velatest.User)
+	}
+
+	err = client.ApproveBuild(context.Background(), "go-vela", "hello-world", 2)
+	if err == nil {
+		t.Errorf("ApproveBuild should have returned err")
+	}
+}
+
+func TestDownstream_nextPage(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		resp *vela.Response
+		want int
+	}{
+		{
+			name: "next page",
+			resp: &vela.Response{Response: &http.Response{Header: http.Header{
+				"Link": []string{`</api/v1/repos/go-vela/hello-world/builds?page=1&per_page=10>; rel="first", </api/v1/repos/go-vela/hello-world/builds?page=3&per_page=10>; rel="next"`},
+			}}},
+			want: 3,
+		},
+		{
+			name: "last page",
+			resp: &vela.Response{Response: &http.Response{Header: http.Header{
+				"Link": []string{`</api/v1/repos/go-vela/hello-world/builds?page=1&per_page=10>; rel="first"`},
+			}}},
+			want: 0,
+		},
+		{
+			name: "no header",
+			resp: &vela.Response{Response: &http.Response{Header: http.Header{}}},
+			want: 0,
+		},
+		{
+			name: "populated",
+			resp: &vela.Response{Response: &http.Response{Header: http.Header{}}, NextPage: 2},
+			want: 2,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := nextPage(test.resp)
+			if got != test.want {
+				t.Errorf("nextPage is %d, want %d", got, test.want)
+			}
+		})
+	}
+}
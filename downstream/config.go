@@ -0,0 +1,308 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/sdk-go/vela"
+)
+
+const (
+	// AuthPersonalAccessToken defines the authentication method
+	// for exchanging an SCM personal access token with Vela.
+	AuthPersonalAccessToken = "pat"
+
+	// AuthToken defines the authentication method
+	// for a Vela issued access token.
+	AuthToken = "token"
+
+	// AuthRefreshToken defines the authentication method for a Vela
+	// issued refresh token that is exchanged for access tokens.
+	AuthRefreshToken = "refresh"
+)
+
+// Config represents the plugin configuration for Config information.
+type Config struct {
+	// Vela server to interact with
+	Server string
+	// method to authenticate with the Vela server
+	AuthMethod string
+	// user token to authenticate with the Vela server
+	Token string
+	// refresh token to exchange for access tokens from the Vela server
+	RefreshToken string
+	// path to a CA bundle for verifying the Vela server certificate
+	CACert string
+	// path to a client certificate for authenticating with the Vela server
+	ClientCert string
+	// path to a client key for authenticating with the Vela server
+	ClientKey string
+	// skip verifying the Vela server certificate
+	InsecureSkipVerify bool
+	// HTTP(S) proxy to reach the Vela server through
+	Proxy string
+	// timeout for each request to the Vela server
+	Timeout time.Duration
+	// named profiles of Vela servers and credentials
+	Profiles map[string]*Profile
+	// depth of builds search in downstream repo
+	Depth int
+	// the app name utilizing this config
+	AppName string
+	// the app version utilizing this config
+	AppVersion string
+}
+
+// New creates a Vela client for triggering builds.
+func (c *Config) New() (*vela.Client, error) {
+	logrus.Trace("creating new Vela client from plugin configuration")
+
+	// create the app string
+	appID := fmt.Sprintf("%s; %s", c.AppName, c.AppVersion)
+
+	// create HTTP client from configuration
+	httpClient, err := c.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// create Vela client from configuration
+	client, err := vela.NewClient(c.Server, appID, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// set the authentication for the Vela client
+	switch c.AuthMethod {
+	case AuthToken:
+		logrus.Debug("setting access token authentication for Vela")
+
+		// set the token for authentication in the Vela client
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#AuthenticationService.SetTokenAuth
+		client.Authentication.SetTokenAuth(c.Token)
+	case AuthRefreshToken:
+		logrus.Debug("setting access and refresh token authentication for Vela")
+
+		// set the tokens for authentication in the Vela client
+		//
+		// the client exchanges the refresh token for a new access token
+		// before any request made with an expired (or empty) access token,
+		// which keeps the plugin authenticated during long report waits
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#AuthenticationService.SetAccessAndRefreshAuth
+		client.Authentication.SetAccessAndRefreshAuth(c.Token, c.RefreshToken)
+	default:
+		// check if a token is provided for authentication
+		if len(c.Token) > 0 {
+			logrus.Debug("setting personal access token authentication for Vela")
+
+			// set the token for authentication in the Vela client
+			//
+			// the client exchanges the personal access token
+			// for a short-lived access token on every request
+			//
+			// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#AuthenticationService.SetPersonalAccessTokenAuth
+			client.Authentication.SetPersonalAccessTokenAuth(c.Token)
+		}
+	}
+
+	return client, nil
+}
+
+// HTTPClient creates an HTTP client for communicating with the Vela server.
+//
+// A nil client is returned when no HTTP configuration is provided,
+// which lets the Vela client fall back to its default HTTP client.
+func (c *Config) HTTPClient() (*http.Client, error) {
+	// check if any HTTP configuration is provided
+	if len(c.CACert) == 0 && len(c.ClientCert) == 0 && len(c.Proxy) == 0 &&
+		!c.InsecureSkipVerify && c.Timeout == 0 {
+		return nil, nil
+	}
+
+	logrus.Trace("creating new HTTP client from plugin configuration")
+
+	// create the TLS configuration for the client
+	//
+	//nolint:gosec // skipping verification is an explicit user choice
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	// check if a CA bundle is provided
+	if len(c.CACert) > 0 {
+		logrus.Debugf("loading CA bundle from %s", c.CACert)
+
+		bundle, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA bundle %s: %w", c.CACert, err)
+		}
+
+		// start from the system certificates so public CAs remain trusted
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CACert)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	// check if a client certificate is provided
+	if len(c.ClientCert) > 0 {
+		logrus.Debugf("loading client certificate from %s", c.ClientCert)
+
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate %s: %w", c.ClientCert, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// check if certificate verification is disabled
+	if c.InsecureSkipVerify {
+		logrus.Warn("!!! TLS certificate verification for the Vela server is DISABLED !!!")
+		logrus.Warn("!!! the connection is vulnerable to interception - do not use outside of testing !!!")
+	}
+
+	// create the transport for the client
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	// check if a proxy is provided
+	if len(c.Proxy) > 0 {
+		// the proxy is left out of the error since it may contain credentials
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("config proxy is not a valid url")
+		}
+
+		logrus.Debugf("using proxy %s for Vela", proxy.Redacted())
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	// set the default timeout for the client
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+// Validate verifies the Config is properly configured.
+func (c *Config) Validate() error {
+	logrus.Trace("validating config configuration")
+
+	// verify server is provided
+	if len(c.Server) == 0 {
+		return fmt.Errorf("no config server provided")
+	}
+
+	// check to make sure it's a valid url
+	_, err := url.ParseRequestURI(c.Server)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid url", c.Server)
+	}
+
+	// verify client certificate and key are provided together
+	if (len(c.ClientCert) == 0) != (len(c.ClientKey) == 0) {
+		return fmt.Errorf("config client certificate and key must be provided together")
+	}
+
+	// check to make sure the proxy is a valid url
+	if len(c.Proxy) > 0 {
+		// the proxy is left out of the error since it may contain credentials
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil || len(proxy.Host) == 0 {
+			return fmt.Errorf("config proxy is not a valid url")
+		}
+	}
+
+	// verify timeout is not negative
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid config timeout provided: %s", c.Timeout)
+	}
+
+	// verify depth is not negative
+	if c.Depth < 0 {
+		return fmt.Errorf("invalid config depth provided: %d", c.Depth)
+	}
+
+	// verify the credentials for the authentication method are provided
+	switch c.AuthMethod {
+	case "", AuthPersonalAccessToken, AuthToken:
+		// verify token is provided
+		if len(c.Token) == 0 {
+			return fmt.Errorf("no config token provided")
+		}
+	case AuthRefreshToken:
+		// verify refresh token is provided
+		if len(c.RefreshToken) == 0 {
+			return fmt.Errorf("no config refresh token provided for %s authentication", c.AuthMethod)
+		}
+	default:
+		return fmt.Errorf("invalid config auth method provided: %s", c.AuthMethod)
+	}
+
+	// iterate through all provided profiles
+	for name := range c.Profiles {
+		config, err := c.Profile(name)
+		if err != nil {
+			return err
+		}
+
+		// validate the configuration for the profile
+		err = config.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid config profile %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Secrets returns the list of secret values
+// provided for the configuration and profiles.
+func (c *Config) Secrets() []string {
+	secrets := []string{c.Token, c.RefreshToken}
+
+	// capture the password for the proxy
+	proxy, err := url.Parse(c.Proxy)
+	if err == nil {
+		password, _ := proxy.User.Password()
+
+		secrets = append(secrets, password)
+	}
+
+	// iterate through all provided profiles
+	for name := range c.Profiles {
+		config, err := c.Profile(name)
+		if err != nil {
+			continue
+		}
+
+		secrets = append(secrets, config.Token, config.RefreshToken)
+	}
+
+	return secrets
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package downstream provides the ability to trigger builds
+// for downstream repos in Vela and wait on their statuses.
+//
+// It powers the vela-downstream plugin and can be imported
+// by other tools to trigger downstream builds:
+//
+//	p, err := downstream.New(
+//		downstream.WithBuild(&downstream.Build{
+//			Branch: "main",
+//			Event:  "push",
+//			Status: []string{"success"},
+//			// statuses the triggered builds must complete with
+//			TargetStatus: []string{"success"},
+//			Timeout:      30 * time.Minute,
+//		}),
+//		downstream.WithConfig(&downstream.Config{
+//			Server:     "https://vela.example.com",
+//			AuthMethod: downstream.AuthPersonalAccessToken,
+//			Token:      os.Getenv("VELA_TOKEN"),
+//		}),
+//		downstream.WithRepos("go-vela/hello-world"),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//
+//	triggered, err := p.Trigger(ctx)
+//	if err != nil {
+//		return err
+//	}
+//
+//	return p.Wait(ctx, triggered)
+//
+// Errors returned from the package wrap the typed errors,
+// i.e. ErrBuildNotFound, to be inspected with errors.Is.
+package downstream
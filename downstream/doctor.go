@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diagnosis represents the result of a single doctor check.
+type diagnosis struct {
+	// description of the check
+	check string
+	// error returned from the check
+	err error
+	// remediation for a failed check
+	hint string
+}
+
+// Doctor diagnoses the plugin configuration by checking the Vela server
+// reachability and version, the token, the token user and access to each
+// repo. The result of every check is written to the provided writer.
+func (p *Plugin) Doctor(ctx context.Context, w io.Writer) error {
+	// create new list to store the diagnoses
+	diagnoses := []*diagnosis{}
+
+	// verify the plugin is properly configured
+	err := p.Validate()
+
+	diagnoses = append(diagnoses, &diagnosis{
+		check: "plugin configuration is valid",
+		err:   err,
+		hint:  "fix the plugin parameters reported in the error",
+	})
+
+	// only continue diagnosing a valid configuration
+	if err == nil {
+		diagnoses = append(diagnoses, p.diagnose(ctx)...)
+	}
+
+	// write the result of every check
+	failures := 0
+
+	for _, d := range diagnoses {
+		if d.err == nil {
+			fmt.Fprintf(w, "[PASS] %s\n", d.check)
+
+			continue
+		}
+
+		failures++
+
+		fmt.Fprintf(w, "[FAIL] %s\n", d.check)
+		fmt.Fprintf(w, "       error: %v\n", d.err)
+		fmt.Fprintf(w, "       hint:  %s\n", d.hint)
+	}
+
+	// check if any checks failed
+	if failures > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", failures)
+	}
+
+	fmt.Fprintln(w, "no problems found")
+
+	return nil
+}
+
+// diagnose runs the doctor checks for each profile used by the configured repos.
+func (p *Plugin) diagnose(ctx context.Context) []*diagnosis {
+	// create new list to store the diagnoses
+	diagnoses := []*diagnosis{}
+
+	// parse list of repos to diagnose
+	repos, err := p.Repo.Parse(p.Build.Branch)
+	if err != nil {
+		return append(diagnoses, &diagnosis{
+			check: "repos are parsed",
+			err:   err,
+			hint:  "provide repos in the <org>/<repo> format",
+		})
+	}
+
+	// group the repos by profile in the order they are provided
+	profiles := []string{}
+	grouped := make(map[string][]*Target)
+
+	for _, repo := range repos {
+		if _, ok := grouped[repo.Profile]; !ok {
+			profiles = append(profiles, repo.Profile)
+		}
+
+		grouped[repo.Profile] = append(grouped[repo.Profile], repo)
+	}
+
+	// iterate through each profile used by the repos
+	for _, profile := range profiles {
+		config, err := p.Config.Profile(profile)
+		if err != nil {
+			diagnoses = append(diagnoses, &diagnosis{
+				check: fmt.Sprintf("profile %s is configured", profile),
+				err:   err,
+				hint:  "add the profile to the profiles parameter",
+			})
+
+			continue
+		}
+
+		diagnoses = append(diagnoses, p.diagnoseProfile(ctx, profile, config, grouped[profile])...)
+	}
+
+	return diagnoses
+}
+
+// diagnoseProfile runs the doctor checks for the provided profile and repos.
+func (p *Plugin) diagnoseProfile(ctx context.Context, profile string, config *Config, repos []*Target) []*diagnosis {
+	// create the prefix for the checks of the profile
+	prefix := config.Server
+	if len(profile) > 0 {
+		prefix = fmt.Sprintf("%s (profile %s)", config.Server, profile)
+	}
+
+	// create new Vela client from profile configuration
+	client, err := p.client(profile)
+	if err != nil {
+		return []*diagnosis{{
+			check: fmt.Sprintf("%s: client is created", prefix),
+			err:   err,
+			hint:  "verify the server and certificate parameters",
+		}}
+	}
+
+	// verify the server is reachable
+	version, err := client.Version(ctx)
+	if err != nil {
+		version = "unknown"
+	}
+
+	diagnoses := []*diagnosis{{
+		check: fmt.Sprintf("%s: server is reachable (version %s)", prefix, version),
+		err:   err,
+		hint:  "verify the server parameter along with any proxy, ca_cert and request_timeout parameters",
+	}}
+
+	// skip the remaining checks since the server is unreachable
+	if err != nil {
+		return diagnoses
+	}
+
+	// verify the token is valid
+	err = client.Authenticate(ctx)
+
+	diagnoses = append(diagnoses, &diagnosis{
+		check: fmt.Sprintf("%s: %s token is valid", prefix, authMethod(config.AuthMethod)),
+		err:   err,
+		hint:  "provide a token that is not expired and matches the auth_method parameter",
+	})
+
+	// skip the remaining checks since the token is invalid
+	if err != nil {
+		return diagnoses
+	}
+
+	// verify the token belongs to a Vela user
+	user, err := client.GetCurrentUser(ctx)
+
+	diagnoses = append(diagnoses, &diagnosis{
+		check: fmt.Sprintf("%s: token user %s exists in Vela", prefix, strings.TrimSpace(user.GetName())),
+		err:   err,
+		hint:  "log into Vela as the user that owns the token at least once",
+	})
+
+	// skip the remaining checks since the user is unknown
+	if err != nil {
+		return diagnoses
+	}
+
+	// iterate through each repo for the profile
+	for _, repo := range repos {
+		diagnoses = append(diagnoses, &diagnosis{
+			check: fmt.Sprintf("%s: repo %s is active and readable", prefix, repo.GetFullName()),
+			err:   p.verifyRepo(ctx, client, repo),
+			hint:  fmt.Sprintf("enable the repo in Vela and grant %s write access to it", user.GetName()),
+		})
+	}
+
+	return diagnoses
+}
+
+// authMethod returns the authentication method with the default applied.
+func authMethod(method string) string {
+	if len(method) == 0 {
+		return AuthPersonalAccessToken
+	}
+
+	return method
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-vela/server/constants"
+)
+
+func TestDownstream_Plugin_Doctor(t *testing.T) {
+	// setup context
+	s := preflightServer(t)
+	defer s.Close()
+
+	buf := new(bytes.Buffer)
+
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world"},
+		},
+	}
+
+	// run test
+	err := p.Doctor(context.Background(), buf)
+	if err != nil {
+		t.Errorf("Doctor returned err: %v\n%s", err, buf.String())
+	}
+
+	for _, want := range []string{
+		"[PASS] plugin configuration is valid",
+		"server is reachable (version v0.27.5)",
+		"pat token is valid",
+		"token user octocat exists in Vela",
+		"repo go-vela/hello-world is active and readable",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Doctor output is %s, want %s", buf.String(), want)
+		}
+	}
+}
+
+func TestDownstream_Plugin_Doctor_Problems(t *testing.T) {
+	// setup context
+	s := preflightServer(t)
+	defer s.Close()
+
+	buf := new(bytes.Buffer)
+
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world", "go-vela/missing"},
+		},
+	}
+
+	// run test
+	err := p.Doctor(context.Background(), buf)
+	if err == nil {
+		t.Errorf("Doctor should have returned err")
+	}
+
+	if !strings.Contains(buf.String(), "[FAIL] "+s.URL+": repo go-vela/missing is active and readable") {
+		t.Errorf("Doctor output is %s, want failure for go-vela/missing", buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "hint:") {
+		t.Errorf("Doctor output is %s, want hint", buf.String())
+	}
+}
+
+func TestDownstream_Plugin_Doctor_Unreachable(t *testing.T) {
+	// setup types
+	buf := new(bytes.Buffer)
+
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: "http://127.0.0.1:1",
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world"},
+		},
+	}
+
+	// run test
+	err := p.Doctor(context.Background(), buf)
+	if err == nil {
+		t.Errorf("Doctor should have returned err")
+	}
+
+	if strings.Contains(buf.String(), "token is valid") {
+		t.Errorf("Doctor output is %s, want no token check for unreachable server", buf.String())
+	}
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-vela/sdk-go/vela"
+)
+
+var (
+	// ErrConfig defines the error returned when
+	// the plugin is not properly configured.
+	ErrConfig = errors.New("invalid configuration")
+
+	// ErrBuildNotFound defines the error returned when no
+	// build matching the configuration is found to restart.
+	ErrBuildNotFound = errors.New("build not found")
+
+	// ErrTrigger defines the error returned when
+	// a downstream build is unable to be restarted.
+	ErrTrigger = errors.New("unable to trigger downstream build")
+
+	// ErrDownstreamFailed defines the error returned when a triggered
+	// build completes without matching the target statuses.
+	ErrDownstreamFailed = errors.New("downstream build failed")
+
+	// ErrReportTimeout defines the error returned when the triggered
+	// builds do not complete before the build check timeout.
+	ErrReportTimeout = errors.New("timeout")
+
+	// ErrUnauthorized defines the error returned when
+	// the Vela server rejects the provided credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden defines the error returned when the
+	// token user lacks permission for the request.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrNotFound defines the error returned when the
+	// requested resource does not exist in Vela.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict defines the error returned when the request
+	// conflicts with the current state of the resource.
+	ErrConflict = errors.New("conflict")
+
+	// ErrServer defines the error returned when the
+	// Vela server fails to handle the request.
+	ErrServer = errors.New("server error")
+
+	// ErrTimeout defines the error returned when the
+	// Vela server does not respond in time.
+	ErrTimeout = errors.New("timeout")
+
+	// ErrLoop defines the error returned when triggering a
+	// downstream repo would create a cyclic downstream chain.
+	ErrLoop = errors.New("cyclic downstream chain")
+
+	// ErrRepoInactive defines the error returned when
+	// a downstream repo is not active in Vela.
+	ErrRepoInactive = errors.New("repo is inactive")
+)
+
+// hints represents the remediation for each category of failure.
+var hints = map[error]string{
+	ErrUnauthorized: "verify the token is valid and not expired, and the token user has logged into Vela at least once",
+	ErrForbidden:    "grant the token user write access to the repo, or admin access to approve builds",
+	ErrNotFound:     "verify the repo and build exist, the repo is active in Vela and the token user has access to it",
+	ErrConflict:     "the build may already be running or the repo may have reached its build limit, retry once it completes",
+	ErrServer:       "the Vela server failed to handle the request, retry later or contact your Vela administrators",
+	ErrTimeout:      "the Vela server did not respond in time, raise the request_timeout or verify network access to the server",
+	ErrRepoInactive: "activate the repo in Vela or remove it from the repos",
+}
+
+// APIError represents a classified failure from the Vela API.
+type APIError struct {
+	// operation that failed
+	Op string
+	// HTTP status code returned from the Vela server
+	StatusCode int
+	// category of the failure, i.e. ErrNotFound
+	Kind error
+	// remediation for the failure
+	Hint string
+	// error returned from the Vela client
+	Err error
+}
+
+// Error returns the message for the failure with the remediation.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %v (hint: %s)", e.Op, e.Err, e.Hint)
+}
+
+// Unwrap returns the category of the failure along with the
+// original error so both can be inspected with errors.Is.
+func (e *APIError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// classify wraps the error returned from a Vela API call for the
+// operation in an APIError when the failure can be classified.
+//
+// Errors that can not be classified are wrapped with the operation.
+func classify(op string, resp *vela.Response, err error) error {
+	// check if an error was provided
+	if err == nil {
+		return nil
+	}
+
+	// capture the category of the failure
+	status, kind := category(resp, err)
+	if kind == nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &APIError{
+		Op:         op,
+		StatusCode: status,
+		Kind:       kind,
+		Hint:       hints[kind],
+		Err:        err,
+	}
+}
+
+// category returns the HTTP status code and the category of
+// the failure for the error returned from a Vela API call.
+func category(resp *vela.Response, err error) (int, error) {
+	// check if the request timed out
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return 0, ErrTimeout
+	}
+
+	// check if no response was received
+	if resp == nil || resp.Response == nil {
+		// the Vela client authenticates before sending each request,
+		// so a failure without a response that was not a transport
+		// failure was returned from exchanging the credentials
+		var urlErr *url.Error
+		if !errors.As(err, &urlErr) {
+			return http.StatusUnauthorized, ErrUnauthorized
+		}
+
+		return 0, nil
+	}
+
+	// capture the category from the status code
+	switch status := resp.StatusCode; {
+	case status == http.StatusUnauthorized && permission(err):
+		// the Vela server responds with unauthorized,
+		// not forbidden, when the user lacks permission
+		return status, ErrForbidden
+	case status == http.StatusUnauthorized:
+		return status, ErrUnauthorized
+	case status == http.StatusForbidden:
+		return status, ErrForbidden
+	case status == http.StatusNotFound:
+		return status, ErrNotFound
+	case status == http.StatusConflict:
+		return status, ErrConflict
+	case status == http.StatusRequestTimeout, status == http.StatusGatewayTimeout:
+		return status, ErrTimeout
+	case status >= http.StatusInternalServerError:
+		return status, ErrServer
+	default:
+		return status, nil
+	}
+}
+
+// permission returns whether the error returned from a Vela API
+// call is for a user lacking permission for the repo, i.e.
+//
+//	user octocat does not have 'write' permissions for the repo go-vela/hello-world
+func permission(err error) bool {
+	msg := err.Error()
+
+	return strings.Contains(msg, "does not have '") && strings.Contains(msg, "' permissions for")
+}
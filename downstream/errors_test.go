@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-vela/sdk-go/vela"
+)
+
+func TestDownstream_classify(t *testing.T) {
+	// setup types
+	failure := errors.New("unable to restart build")
+
+	// setup tests
+	tests := []struct {
+		name string
+		resp *vela.Response
+		err  error
+		want error
+	}{
+		{
+			name: "unauthorized",
+			resp: &vela.Response{Response: &http.Response{StatusCode: http.StatusUnauthorized}},
+			err:  failure,
+			want: ErrUnauthorized,
+		},
+		{
+			name: "forbidden",
+			resp: &vela.Response{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			err:  failure,
+			want: ErrForbidden,
+		},
+		{
+			name: "not found",
+			resp: &vela.Response{Response: &http.Response{StatusCode: http.StatusNotFound}},
+			err:  failure,
+			want: ErrNotFound,
+		},
+		{
+			name: "conflict",
+			resp: &vela.Response{Response: &http.Response{StatusCode: http.StatusConflict}},
+			err:  failure,
+			want: ErrConflict,
+		},
+		{
+			name: "server",
+			resp: &vela.Response{Response: &http.Response{StatusCode: http.StatusBadGateway}},
+			err:  failure,
+			want: ErrServer,
+		},
+		{
+			name: "gateway timeout",
+			resp: &vela.Response{Response: &http.Response{StatusCode: http.StatusGatewayTimeout}},
+			err:  failure,
+			want: ErrTimeout,
+		},
+		{
+			name: "client timeout",
+			err:  &url.Error{Op: "Get", URL: "http://vela.localhost.com", Err: context.DeadlineExceeded},
+			want: ErrTimeout,
+		},
+		{
+			name: "authentication",
+			err:  errors.New("unable to authenticate: user not found"),
+			want: ErrUnauthorized,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := classify("unable to restart build go-vela/hello-world/1", test.resp, test.err)
+
+			if !errors.Is(got, test.want) {
+				t.Errorf("classify is %v, want %v", got, test.want)
+			}
+
+			if !errors.Is(got, test.err) {
+				t.Errorf("classify is %v, want wrapped %v", got, test.err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(got, &apiErr) || len(apiErr.Hint) == 0 {
+				t.Errorf("classify is %v, want APIError with hint", got)
+			}
+
+			if !strings.Contains(got.Error(), apiErr.Hint) {
+				t.Errorf("classify is %v, want hint %s", got, apiErr.Hint)
+			}
+		})
+	}
+}
+
+func TestDownstream_classify_Permission(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{
+			name: "write",
+			body: `{"error":"user octocat does not have 'write' permissions for the repo go-vela/hello-world"}`,
+			want: ErrForbidden,
+		},
+		{
+			name: "admin",
+			body: `{"error":"user octocat does not have 'admin' permissions for the repo go-vela/hello-world"}`,
+			want: ErrForbidden,
+		},
+		{
+			name: "token",
+			body: `{"error":"unable to parse token: token is expired"}`,
+			want: ErrUnauthorized,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(test.body)),
+			}
+
+			// capture the error the Vela client returns for the response
+			err := vela.CheckResponse(resp)
+
+			got := classify("unable to restart build go-vela/hello-world/1", &vela.Response{Response: resp}, err)
+
+			if !errors.Is(got, test.want) {
+				t.Errorf("classify is %v, want %v", got, test.want)
+			}
+
+			if !strings.Contains(got.Error(), hints[test.want]) {
+				t.Errorf("classify is %v, want hint %s", got, hints[test.want])
+			}
+		})
+	}
+}
+
+func TestDownstream_classify_Unclassified(t *testing.T) {
+	// setup types
+	failure := &url.Error{Op: "Get", URL: "http://vela.localhost.com", Err: errors.New("connection refused")}
+
+	// run test
+	got := classify("unable to get build go-vela/hello-world/1", nil, failure)
+
+	var apiErr *APIError
+	if errors.As(got, &apiErr) {
+		t.Errorf("classify is %v, want unclassified error", got)
+	}
+
+	if !errors.Is(got, failure) {
+		t.Errorf("classify is %v, want wrapped %v", got, failure)
+	}
+
+	if got.Error() != fmt.Sprintf("unable to get build go-vela/hello-world/1: %v", failure) {
+		t.Errorf("classify is %v, want operation prefix", got)
+	}
+}
+
+func TestDownstream_classify_NoError(t *testing.T) {
+	// run test
+	err := classify("unable to get build go-vela/hello-world/1", nil, nil)
+	if err != nil {
+		t.Errorf("classify is %v, want nil", err)
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+const (
+	// OrderNewest defines the order selecting
+	// the newest build matching the filters.
+	OrderNewest = "newest"
+
+	// OrderOldest defines the order selecting
+	// the oldest build matching the filters.
+	OrderOldest = "oldest"
+)
+
+// filter represents the filters beyond the branch,
+// event and status a build must match to be restarted.
+type filter struct {
+	// builds for the event action
+	action string
+	// builds created after the unix timestamp
+	after int64
+	// builds created before the unix timestamp
+	before int64
+	// builds for a commit from the author
+	author string
+	// builds triggered by the sender
+	sender string
+	// builds for a commit message matching the expression
+	message *regexp.Regexp
+	// builds for the pull request number
+	pullRequest int
+	// builds triggered by the schedule name
+	schedule string
+}
+
+// filter returns the filters for the Build at the provided time.
+func (b *Build) filter(now time.Time) (*filter, error) {
+	_, action := b.event()
+
+	f := &filter{
+		action: action,
+		author: b.Author,
+		sender: b.Sender,
+	}
+
+	// check if a created after timestamp is provided
+	if !b.CreatedAfter.IsZero() {
+		f.after = b.CreatedAfter.Unix()
+	}
+
+	// check if a max age is provided
+	if b.MaxAge > 0 {
+		// use the max age when it is more recent than the timestamp
+		f.after = max(f.after, now.Add(-b.MaxAge).Unix())
+	}
+
+	// check if a created before timestamp is provided
+	if !b.CreatedBefore.IsZero() {
+		f.before = b.CreatedBefore.Unix()
+	}
+
+	// check if a commit message expression is provided
+	if len(b.Message) > 0 {
+		re, err := regexp.Compile(b.Message)
+		if err != nil {
+			return nil, fmt.Errorf("invalid build message expression %s: %w", b.Message, err)
+		}
+
+		f.message = re
+	}
+
+	return f, nil
+}
+
+// match returns whether the provided build matches the filters.
+func (f *filter) match(b *api.Build) bool {
+	switch {
+	case len(f.action) > 0 && !strings.EqualFold(f.action, b.GetEventAction()):
+		return false
+	case f.after > 0 && b.GetCreated() <= f.after:
+		return false
+	case f.before > 0 && b.GetCreated() >= f.before:
+		return false
+	case len(f.author) > 0 && !strings.EqualFold(f.author, b.GetAuthor()):
+		return false
+	case len(f.sender) > 0 && !strings.EqualFold(f.sender, b.GetSender()):
+		return false
+	case f.message != nil && !f.message.MatchString(b.GetMessage()):
+		return false
+	case f.pullRequest > 0 && f.pullRequest != pullRequest(b):
+		return false
+	case len(f.schedule) > 0 && f.schedule != b.GetDeploy():
+		return false
+	}
+
+	return true
+}
+
+// pullRequest returns the pull request number from the ref
+// of the provided build, i.e. 123 from refs/pull/123/head,
+// or zero when the build is not for a pull request.
+func pullRequest(b *api.Build) int {
+	parts := strings.Split(b.GetRef(), "/")
+	if len(parts) < 3 || parts[0] != "refs" || parts[1] != "pull" {
+		return 0
+	}
+
+	number, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0
+	}
+
+	return number
+}
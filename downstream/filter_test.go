@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+)
+
+func TestDownstream_Build_filter(t *testing.T) {
+	// setup types
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	b := &Build{
+		CreatedAfter:  now.Add(-72 * time.Hour),
+		CreatedBefore: now.Add(-time.Hour),
+		MaxAge:        48 * time.Hour,
+	}
+
+	// run test
+	got, err := b.filter(now)
+	if err != nil {
+		t.Errorf("filter returned err: %v", err)
+	}
+
+	if got.after != now.Add(-48*time.Hour).Unix() {
+		t.Errorf("filter after is %d, want max age %d", got.after, now.Add(-48*time.Hour).Unix())
+	}
+
+	if got.before != now.Add(-time.Hour).Unix() {
+		t.Errorf("filter before is %d, want %d", got.before, now.Add(-time.Hour).Unix())
+	}
+}
+
+func TestDownstream_Build_filter_InvalidMessage(t *testing.T) {
+	// setup types
+	b := &Build{
+		Message: "release-(",
+	}
+
+	// run test
+	_, err := b.filter(time.Now())
+	if err == nil {
+		t.Errorf("filter should have returned err")
+	}
+}
+
+func TestDownstream_filter_match(t *testing.T) {
+	// setup types
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	b := &Build{
+		MaxAge:  24 * time.Hour,
+		Author:  "octocat",
+		Sender:  "vela-bot",
+		Message: `^chore\(release\)`,
+	}
+
+	f, err := b.filter(now)
+	if err != nil {
+		t.Errorf("filter returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name  string
+		build *api.Build
+		want  bool
+	}{
+		{
+			name:  "match",
+			build: filterBuild(now.Add(-time.Hour), "Octocat", "vela-bot", "chore(release): v1.2.3"),
+			want:  true,
+		},
+		{
+			name:  "too old",
+			build: filterBuild(now.Add(-48*time.Hour), "octocat", "vela-bot", "chore(release): v1.2.3"),
+			want:  false,
+		},
+		{
+			name:  "author",
+			build: filterBuild(now.Add(-time.Hour), "hubot", "vela-bot", "chore(release): v1.2.3"),
+			want:  false,
+		},
+		{
+			name:  "sender",
+			build: filterBuild(now.Add(-time.Hour), "octocat", "octocat", "chore(release): v1.2.3"),
+			want:  false,
+		},
+		{
+			name:  "message",
+			build: filterBuild(now.Add(-time.Hour), "octocat", "vela-bot", "fix: typo"),
+			want:  false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := f.match(test.build)
+			if got != test.want {
+				t.Errorf("match is %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+// filterBuild creates a build on the main branch with the provided
+// created time, commit author, sender and commit message.
+func filterBuild(created time.Time, author, sender, message string) *api.Build {
+	return &api.Build{
+		Branch:  vela.String("main"),
+		Created: vela.Int64(created.Unix()),
+		Author:  vela.String(author),
+		Sender:  vela.String(sender),
+		Message: vela.String(message),
+	}
+}
+
+func TestDownstream_pullRequest(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{ref: "refs/pull/123/head", want: 123},
+		{ref: "refs/pull/7/merge", want: 7},
+		{ref: "refs/heads/main", want: 0},
+		{ref: "refs/pull/abc/head", want: 0},
+		{ref: "", want: 0},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.ref, func(t *testing.T) {
+			got := pullRequest(&api.Build{Ref: vela.String(test.ref)})
+			if got != test.want {
+				t.Errorf("pullRequest is %d, want %d", got, test.want)
+			}
+		})
+	}
+}
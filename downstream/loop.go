@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+)
+
+// detectLoops verifies triggering the provided repos does not
+// create a cyclic downstream chain for the upstream build.
+//
+// Vela records no origin for a restarted build other than the build
+// it was restarted from and the user who restarted it, so only the
+// upstream build itself is refused, i.e. a repo that may restart a
+// build on the branch of the upstream build. The rest of the origin
+// chain is inferred and logged as a warning by origins.
+func (p *Plugin) detectLoops(ctx context.Context, repos []*Target) error {
+	// early exit if loop detection is disabled or no upstream build is provided
+	if !p.Build.DetectLoops || len(p.Build.Upstream) == 0 || len(repos) == 0 {
+		return nil
+	}
+
+	logrus.Debugf("inspecting origin chain of upstream build %s", p.Build.Upstream)
+
+	parsed, err := ParseTriggered(p.Build.Upstream)
+	if err != nil {
+		return err
+	}
+
+	upstream := parsed[0]
+
+	// capture the Vela client for the upstream repo
+	client, err := p.client(upstream.Repo.Profile)
+	if err != nil {
+		return err
+	}
+
+	// send API call to capture the upstream build
+	build, err := client.GetBuild(ctx, upstream.Repo.GetOrg(), upstream.Repo.GetName(), upstream.Build.GetNumber())
+	if err != nil {
+		logrus.WithError(err).Warnf("unable to capture upstream build %s, skipping loop detection", upstream)
+
+		return nil
+	}
+
+	// create new list to store the loops found
+	loops := []error{}
+
+	// verify no repo may restart a build on the branch of the upstream build
+	for _, repo := range repos {
+		same, err := p.sameRepo(repo, upstream.Repo)
+		if err != nil {
+			return err
+		}
+
+		if same && sameBranch(repo, build) {
+			loops = append(loops, fmt.Errorf("%w: %s is the upstream repo and branch %s of build %s", ErrLoop, repo.GetFullName(), build.GetBranch(), upstream))
+		}
+	}
+
+	// check if any loops were found
+	if len(loops) > 0 {
+		return fmt.Errorf("refusing to trigger repos in the origin chain of upstream build %s, no builds were restarted:\n%w", upstream, errors.Join(loops...))
+	}
+
+	// warn about the repos inferred as the origin of the upstream build
+	err = p.origins(ctx, upstream, build, repos)
+	if err != nil {
+		logrus.WithError(err).Warnf("unable to inspect origin of upstream build %s", upstream)
+	}
+
+	return nil
+}
+
+// origins logs a warning for every repo inferred as the origin of
+// the provided upstream build. The origin is only inferred, since a
+// token user may restart unrelated builds at the same time, so the
+// repos are still triggered.
+func (p *Plugin) origins(ctx context.Context, upstream *Triggered, build *api.Build, repos []*Target) error {
+	// check if the upstream build was restarted
+	if build.GetParent() == 0 {
+		return nil
+	}
+
+	// capture the Vela client for the upstream repo
+	client, err := p.client(upstream.Repo.Profile)
+	if err != nil {
+		return err
+	}
+
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	// check if the upstream build was restarted by the token user
+	if build.GetSender() != user.GetName() {
+		logrus.Debugf("upstream build %s was restarted by %s, not by a downstream build", upstream, build.GetSender())
+
+		return nil
+	}
+
+	for _, repo := range repos {
+		// capture the Vela client for the repo
+		client, err := p.client(repo.Profile)
+		if err != nil {
+			return err
+		}
+
+		// send API call to capture the latest builds for the repo
+		builds, _, err := client.ListBuilds(ctx, repo.GetOrg(), repo.GetName(), &vela.BuildListOptions{
+			ListOptions: vela.ListOptions{Page: 1, PerPage: 10},
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, b := range builds {
+			if !runningAt(&b, build.GetCreated()) {
+				continue
+			}
+
+			// check if the build was restarted by the token user, since
+			// builds triggered by anything else can't restart the upstream
+			// build with the token
+			if b.GetParent() == 0 || b.GetSender() != user.GetName() {
+				continue
+			}
+
+			p.logger(repo).WithField("running_build", b.GetNumber()).
+				Warnf("build was running when %s restarted upstream build %s, the repo may be in its origin chain", build.GetSender(), upstream)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// sameRepo returns whether the provided repos
+// are the same repo on the same Vela server.
+func (p *Plugin) sameRepo(repo, upstream *Target) (bool, error) {
+	if repo.GetFullName() != upstream.GetFullName() {
+		return false, nil
+	}
+
+	a, err := p.Config.Profile(repo.Profile)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := p.Config.Profile(upstream.Profile)
+	if err != nil {
+		return false, err
+	}
+
+	return a.Server == b.Server, nil
+}
+
+// sameBranch returns whether triggering the provided repo may
+// restart a build on the branch of the provided upstream build,
+// i.e. the repo has no branch or its branch matches the branch of
+// the upstream build.
+func sameBranch(repo *Target, upstream *api.Build) bool {
+	// check if a pull request is provided for the repo
+	if repo.PullRequest > 0 {
+		return repo.PullRequest == pullRequest(upstream)
+	}
+
+	// check if a branch is provided for the repo
+	if len(repo.GetBranch()) == 0 {
+		return true
+	}
+
+	match, err := branchMatcher(repo.GetBranch())
+	if err != nil || match == nil {
+		return repo.GetBranch() == upstream.GetBranch()
+	}
+
+	return match(upstream.GetBranch())
+}
+
+// runningAt returns whether the provided build was
+// running at the provided time, in seconds.
+func runningAt(b *api.Build, t int64) bool {
+	// check if the build started before the time
+	if b.GetStarted() == 0 || b.GetStarted() > t {
+		return false
+	}
+
+	return b.GetFinished() == 0 || b.GetFinished() >= t
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/vela-downstream/downstream/velatest"
+)
+
+// loopServer creates a Vela server with a go-vela/upstream repo
+// with the provided upstream build on the main branch followed by
+// a build on the docs branch, and a go-vela/hello-world repo
+// with the provided build running from 900 to 1100.
+func loopServer(t *testing.T, upstream, running *api.Build) *velatest.Server {
+	t.Helper()
+
+	s := velatest.NewServer()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("upstream")})
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+
+	s.AddBuild("go-vela/upstream", &api.Build{Branch: vela.String("main")})
+
+	upstream.Branch = vela.String("main")
+
+	s.AddBuild("go-vela/upstream", upstream)
+	s.AddBuild("go-vela/upstream", &api.Build{Branch: vela.String("docs")})
+
+	running.Branch = vela.String("main")
+	running.Started = vela.Int64(900)
+	running.Finished = vela.Int64(1100)
+
+	s.AddBuild("go-vela/hello-world", running)
+
+	return s
+}
+
+// restartedBuild returns a build restarted by the token user.
+func restartedBuild() *api.Build {
+	return &api.Build{
+		Parent: vela.Int64(1),
+		Sender: vela.String(velatest.User),
+	}
+}
+
+func TestDownstream_Plugin_detectLoops(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		upstream *api.Build
+		running  *api.Build
+		repos    []string
+		want     error
+		warning  bool
+	}{
+		{
+			name:     "push",
+			upstream: &api.Build{Created: vela.Int64(1000)},
+			running:  restartedBuild(),
+			repos:    []string{"go-vela/hello-world"},
+		},
+		{
+			name:     "upstream repo",
+			upstream: &api.Build{Created: vela.Int64(1000)},
+			running:  restartedBuild(),
+			repos:    []string{"go-vela/hello-world", "go-vela/upstream"},
+			want:     ErrLoop,
+		},
+		{
+			name:     "upstream repo branch pattern",
+			upstream: &api.Build{Created: vela.Int64(1000)},
+			running:  restartedBuild(),
+			repos:    []string{"go-vela/upstream@ma*"},
+			want:     ErrLoop,
+		},
+		{
+			name:     "upstream repo on another branch",
+			upstream: &api.Build{Created: vela.Int64(1000)},
+			running:  restartedBuild(),
+			repos:    []string{"go-vela/upstream@docs"},
+		},
+		{
+			name: "restarted by downstream build",
+			upstream: &api.Build{
+				Created: vela.Int64(1000),
+				Parent:  vela.Int64(1),
+				Sender:  vela.String(velatest.User),
+			},
+			running: restartedBuild(),
+			repos:   []string{"go-vela/hello-world"},
+			warning: true,
+		},
+		{
+			name: "restarted by another user",
+			upstream: &api.Build{
+				Created: vela.Int64(1000),
+				Parent:  vela.Int64(1),
+				Sender:  vela.String("hubot"),
+			},
+			running: restartedBuild(),
+			repos:   []string{"go-vela/hello-world"},
+		},
+		{
+			name: "unrelated running build",
+			upstream: &api.Build{
+				Created: vela.Int64(1000),
+				Parent:  vela.Int64(1),
+				Sender:  vela.String(velatest.User),
+			},
+			running: &api.Build{Sender: vela.String(velatest.User)},
+			repos:   []string{"go-vela/hello-world"},
+		},
+		{
+			name: "running build restarted by another user",
+			upstream: &api.Build{
+				Created: vela.Int64(1000),
+				Parent:  vela.Int64(1),
+				Sender:  vela.String(velatest.User),
+			},
+			running: &api.Build{
+				Parent: vela.Int64(1),
+				Sender: vela.String("hubot"),
+			},
+			repos: []string{"go-vela/hello-world"},
+		},
+		{
+			name: "restarted after downstream build",
+			upstream: &api.Build{
+				Created: vela.Int64(1200),
+				Parent:  vela.Int64(1),
+				Sender:  vela.String(velatest.User),
+			},
+			running: restartedBuild(),
+			repos:   []string{"go-vela/hello-world"},
+		},
+	}
+
+	// setup context
+	out := logrus.StandardLogger().Out
+	defer logrus.SetOutput(out)
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := loopServer(t, test.upstream, test.running)
+			defer s.Close()
+
+			buf := new(bytes.Buffer)
+			logrus.SetOutput(buf)
+
+			p, err := New(
+				WithBuild(&Build{
+					Branch:      "main",
+					Event:       constants.EventPush,
+					Status:      []string{constants.StatusSuccess},
+					Upstream:    "go-vela/upstream/2",
+					DetectLoops: true,
+				}),
+				WithConfig(&Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+				}),
+				WithRepos(test.repos...),
+			)
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			_, err = p.Trigger(context.Background())
+			if !errors.Is(err, test.want) {
+				t.Errorf("Trigger returned err %v, want %v", err, test.want)
+			}
+
+			restarted := s.Calls("POST /api/v1/repos/{org}/{repo}/builds/{build}") > 0
+			if restarted == (test.want != nil) {
+				t.Errorf("Trigger restarted builds is %t, want %t", restarted, test.want == nil)
+			}
+
+			warned := strings.Contains(buf.String(), "may be in its origin chain")
+			if warned != test.warning {
+				t.Errorf("Trigger warned about origin is %t, want %t", warned, test.warning)
+			}
+		})
+	}
+}
+
+func TestDownstream_runningAt(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name  string
+		build *api.Build
+		want  bool
+	}{
+		{
+			name:  "running",
+			build: &api.Build{Started: vela.Int64(900)},
+			want:  true,
+		},
+		{
+			name:  "finished after",
+			build: &api.Build{Started: vela.Int64(900), Finished: vela.Int64(1100)},
+			want:  true,
+		},
+		{
+			name:  "finished before",
+			build: &api.Build{Started: vela.Int64(900), Finished: vela.Int64(950)},
+			want:  false,
+		},
+		{
+			name:  "started after",
+			build: &api.Build{Started: vela.Int64(1100)},
+			want:  false,
+		},
+		{
+			name:  "pending",
+			build: &api.Build{},
+			want:  false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := runningAt(test.build, 1000)
+			if got != test.want {
+				t.Errorf("runningAt is %t, want %t", got, test.want)
+			}
+		})
+	}
+}
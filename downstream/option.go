@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"fmt"
+	"time"
+)
+
+// Option represents a configuration option for the Plugin.
+type Option func(*Plugin) error
+
+// WithBuild sets the build configuration for the Plugin.
+func WithBuild(b *Build) Option {
+	return func(p *Plugin) error {
+		// check if the build configuration is provided
+		if b == nil {
+			return fmt.Errorf("no build configuration provided")
+		}
+
+		p.Build = b
+
+		return nil
+	}
+}
+
+// WithConfig sets the Vela configuration for the Plugin.
+func WithConfig(c *Config) Option {
+	return func(p *Plugin) error {
+		// check if the Vela configuration is provided
+		if c == nil {
+			return fmt.Errorf("no config configuration provided")
+		}
+
+		p.Config = c
+
+		return nil
+	}
+}
+
+// WithRepos sets the list of <org>/<repo> names to trigger for the Plugin.
+func WithRepos(names ...string) Option {
+	return func(p *Plugin) error {
+		p.Repo = &Repo{Names: names}
+
+		return nil
+	}
+}
+
+// WithState sets the state file to record the builds triggered
+// for the provided <org>/<repo>/<number> upstream build to.
+func WithState(path, upstream string) Option {
+	return func(p *Plugin) error {
+		p.State = &State{Path: path, Key: upstream}
+
+		return nil
+	}
+}
+
+// WithClient sets the Vela client for the repos without a profile.
+func WithClient(c Client) Option {
+	return WithProfileClient("", c)
+}
+
+// WithProfileClient sets the Vela client for the repos with the provided profile.
+//
+// This replaces the client the Plugin creates from the Config for the profile.
+func WithProfileClient(profile string, c Client) Option {
+	return func(p *Plugin) error {
+		// check if the Vela client is provided
+		if c == nil {
+			return fmt.Errorf("no client provided for profile %q", profile)
+		}
+
+		p.clients[profile] = c
+
+		return nil
+	}
+}
+
+// WithInterval sets the interval for checking the status of triggered builds.
+func WithInterval(d time.Duration) Option {
+	return func(p *Plugin) error {
+		// check if the interval is valid
+		if d <= 0 {
+			return fmt.Errorf("invalid interval provided: %s", d)
+		}
+
+		p.interval = d
+
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-vela/server/constants"
+)
+
+func TestDownstream_New(t *testing.T) {
+	// setup types
+	b := &Build{
+		Branch: "main",
+		Event:  constants.EventPush,
+		Status: []string{constants.StatusSuccess},
+	}
+
+	c := &Config{
+		Server: "http://vela.localhost.com",
+		Token:  "superSecretVelaToken",
+	}
+
+	client := new(client)
+
+	// run test
+	got, err := New(
+		WithBuild(b),
+		WithConfig(c),
+		WithRepos("go-vela/hello-world@main"),
+		WithClient(client),
+		WithInterval(time.Second),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	if got.Build != b {
+		t.Errorf("New Build is %v, want %v", got.Build, b)
+	}
+
+	if got.Config != c {
+		t.Errorf("New Config is %v, want %v", got.Config, c)
+	}
+
+	if !reflect.DeepEqual(got.Repo, &Repo{Names: []string{"go-vela/hello-world@main"}}) {
+		t.Errorf("New Repo is %v, want go-vela/hello-world@main", got.Repo)
+	}
+
+	if got.clients[""] != client {
+		t.Errorf("New client is %v, want %v", got.clients[""], client)
+	}
+
+	if got.interval != time.Second {
+		t.Errorf("New interval is %s, want %s", got.interval, time.Second)
+	}
+}
+
+func TestDownstream_New_Defaults(t *testing.T) {
+	// run test
+	got, err := New()
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	if got.Build == nil || got.Config == nil || got.Repo == nil {
+		t.Errorf("New should have created default configuration")
+	}
+
+	if got.interval != 30*time.Second {
+		t.Errorf("New interval is %s, want %s", got.interval, 30*time.Second)
+	}
+
+	if got.Build.Timeout != 30*time.Minute {
+		t.Errorf("New timeout is %s, want %s", got.Build.Timeout, 30*time.Minute)
+	}
+
+	got, err = New(WithBuild(&Build{Event: "push"}))
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	if got.Build.Timeout != 30*time.Minute {
+		t.Errorf("New timeout with build is %s, want %s", got.Build.Timeout, 30*time.Minute)
+	}
+}
+
+func TestDownstream_New_Error(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{
+			name: "no build",
+			opt:  WithBuild(nil),
+		},
+		{
+			name: "no config",
+			opt:  WithConfig(nil),
+		},
+		{
+			name: "no client",
+			opt:  WithProfileClient("prod", nil),
+		},
+		{
+			name: "invalid interval",
+			opt:  WithInterval(0),
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New(test.opt)
+			if err == nil {
+				t.Errorf("New should have returned err")
+			}
+		})
+	}
+}
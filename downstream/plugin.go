@@ -0,0 +1,758 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/server/constants"
+)
+
+// defaultTimeout defines the default timeout
+// for waiting on triggered builds.
+const defaultTimeout = 30 * time.Minute
+
+// Plugin represents the configuration loaded for the plugin.
+type Plugin struct {
+	// build arguments loaded for the plugin
+	Build *Build
+	// config arguments loaded for the plugin
+	Config *Config
+	// repo arguments loaded for the plugin
+	Repo *Repo
+	// state arguments loaded for the plugin
+	State *State
+
+	// Vela clients created for the plugin by profile
+	clients map[string]Client
+	// interval for checking the status of triggered builds
+	interval time.Duration
+}
+
+// New creates a Plugin configured with the provided options.
+func New(opts ...Option) (*Plugin, error) {
+	// create new plugin with the default configuration
+	p := &Plugin{
+		Build:    new(Build),
+		Config:   new(Config),
+		Repo:     new(Repo),
+		clients:  make(map[string]Client),
+		interval: 30 * time.Second,
+	}
+
+	// apply all provided options to the plugin
+	for _, opt := range opts {
+		err := opt(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// set the default timeout when none is provided,
+	// since a zero timeout expires before any check
+	if p.Build.Timeout == 0 {
+		p.Build.Timeout = defaultTimeout
+	}
+
+	return p, nil
+}
+
+// Exec formats and runs the commands for triggering builds in Vela.
+func (p *Plugin) Exec(ctx context.Context) error {
+	logrus.Debug("running plugin with provided configuration")
+
+	// trigger the builds for the downstream repos
+	triggered, err := p.Trigger(ctx)
+	if err != nil {
+		return err
+	}
+
+	// early exit if reporting back is not enabled
+	if !p.Build.Report || len(triggered) == 0 {
+		return nil
+	}
+
+	return p.Wait(ctx, triggered)
+}
+
+// Trigger restarts the latest matching build for every downstream repo.
+//
+// Every repo is verified before any build is restarted to avoid
+// only triggering a portion of the downstream repos.
+func (p *Plugin) Trigger(ctx context.Context) ([]*Triggered, error) {
+	// parse list of repos to trigger builds on
+	repos, err := p.Repo.Parse(p.Build.Branch)
+	if err != nil {
+		return nil, err
+	}
+
+	// capture the builds already triggered for the upstream build
+	triggered, repos, err := p.resume(repos)
+	if err != nil {
+		return nil, err
+	}
+
+	// resolve the default branch for repos without a branch
+	err = p.resolveBranches(ctx, repos)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the repos do not restart the upstream build
+	err = p.detectLoops(ctx, repos)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify every repo and capture the builds to restart
+	found, err := p.Preflight(ctx, repos)
+	if err != nil {
+		return nil, err
+	}
+
+	// iterate through each repo from provided configuration
+	for _, repo := range repos {
+		// capture the build to restart for the repo
+		build, ok := found[repo]
+		if !ok {
+			continue
+		}
+
+		// capture the Vela client for the repo
+		client, err := p.client(repo.Profile)
+		if err != nil {
+			return triggered, err
+		}
+
+		// create structured logger for the repo with the build to restart
+		logger := p.logger(repo).WithFields(logrus.Fields{
+			"source_build": build.GetNumber(),
+			"status":       build.GetStatus(),
+		})
+
+		logger.Info("restarting build")
+
+		// send API call to restart the latest build for the repo
+		b, err := client.RestartBuild(ctx, repo.GetOrg(), repo.GetName(), build.GetNumber())
+		if err != nil {
+			return triggered, fmt.Errorf("%w: %w", ErrTrigger, err)
+		}
+
+		triggered = append(triggered, &Triggered{
+			Repo:     repo,
+			Source:   build,
+			Build:    b,
+			Attempts: 1,
+		})
+
+		logger.WithFields(logrus.Fields{
+			"new_build": b.GetNumber(),
+			"status":    b.GetStatus(),
+		}).Info("new build created")
+
+		// record the triggered builds for the upstream build
+		p.checkpoint(triggered)
+	}
+
+	return triggered, nil
+}
+
+// resolveBranches sets the branch for every provided repo without
+// a branch to the default branch configured for the repo in Vela.
+//
+// Without a branch, builds from any branch are searched which
+// could restart a build from a feature branch by accident.
+func (p *Plugin) resolveBranches(ctx context.Context, repos []*Target) error {
+	// early exit if resolving the default branch is disabled
+	if !p.Build.DefaultBranch {
+		return nil
+	}
+
+	for _, repo := range repos {
+		// check if a branch or pull request is provided for the repo
+		if len(repo.GetBranch()) > 0 || repo.PullRequest > 0 {
+			continue
+		}
+
+		// capture the Vela client for the repo
+		client, err := p.client(repo.Profile)
+		if err != nil {
+			return err
+		}
+
+		// send API call to capture the repo
+		r, err := client.GetRepo(ctx, repo.GetOrg(), repo.GetName())
+		if err != nil {
+			return fmt.Errorf("unable to resolve default branch for %s: %w", repo.GetFullName(), err)
+		}
+
+		// check if the repo has a default branch
+		if len(r.GetBranch()) == 0 {
+			return fmt.Errorf("unable to resolve default branch for %s: no default branch configured", repo.GetFullName())
+		}
+
+		repo.SetBranch(r.GetBranch())
+
+		p.logger(repo).Info("resolved default branch for repo")
+	}
+
+	return nil
+}
+
+// resume returns the builds recorded as triggered for the upstream
+// build in the state file along with the repos left to trigger.
+func (p *Plugin) resume(repos []*Target) ([]*Triggered, []*Target, error) {
+	// create new list to store the triggered builds
+	triggered := []*Triggered{}
+
+	// early exit if no state file is provided
+	if p.State == nil {
+		return triggered, repos, nil
+	}
+
+	// capture the builds recorded for the upstream build
+	recorded, err := p.State.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// create new list to store the repos left to trigger
+	remaining := []*Target{}
+
+	for _, repo := range repos {
+		// find the build recorded for the repo
+		idx := slices.IndexFunc(recorded, func(t *Triggered) bool {
+			return t.Repo.Profile == repo.Profile && t.Repo.GetFullName() == repo.GetFullName()
+		})
+
+		if idx < 0 {
+			remaining = append(remaining, repo)
+
+			continue
+		}
+
+		p.logger(repo).WithField("new_build", recorded[idx].Build.GetNumber()).
+			Infof("resuming build triggered for upstream build %s", p.State.Key)
+
+		// keep the attempts recorded for the build
+		recorded[idx].Repo = repo
+
+		triggered = append(triggered, recorded[idx])
+	}
+
+	return triggered, remaining, nil
+}
+
+// checkpoint records the provided builds as triggered
+// for the upstream build when a state file is provided.
+//
+// The builds have already been restarted, so a failure
+// to record them is logged rather than returned.
+func (p *Plugin) checkpoint(triggered []*Triggered) {
+	// early exit if no state file is provided
+	if p.State == nil {
+		return
+	}
+
+	err := p.State.Save(triggered)
+	if err != nil {
+		logrus.WithError(err).Warn("unable to record triggered builds, a retry will trigger them again")
+	}
+}
+
+// Wait checks the build statuses of all the provided triggered builds until every
+// build matches the target statuses, a build fails or the build check timeout is
+// reached. The build for each triggered build is updated with the latest status.
+func (p *Plugin) Wait(ctx context.Context, triggered []*Triggered) error {
+	// set timeout for the triggered builds
+	ctx, cancel := context.WithTimeout(ctx, p.Build.Timeout)
+	defer cancel()
+
+	logrus.Infof("waiting for %s to check status of downstream builds...", p.interval)
+
+	// sleep to allow for all restart processing
+	err := p.sleep(ctx)
+	if err != nil {
+		return err
+	}
+
+	successMap := make(map[*Triggered]bool)
+
+	// track the triggered builds already reported pending approval
+	approvals := make(map[*Triggered]bool)
+
+	// track the triggered builds pending approval on the last check
+	waiting := []string{}
+
+	// report the outcome of every triggered build once finished
+	defer p.report(triggered)
+
+	for {
+		logrus.Debug("checking build statuses of downstream builds...")
+
+		// capture the triggered builds that have not matched yet
+		pending := []*Triggered{}
+
+		for _, t := range triggered {
+			if !successMap[t] {
+				pending = append(pending, t)
+			}
+		}
+
+		builds, err := p.poll(ctx, pending)
+		if err != nil {
+			return awaiting(p.timeout(ctx, err), waiting)
+		}
+
+		// reset the builds pending approval for the check
+		waiting = []string{}
+
+		for i, t := range pending {
+			build := builds[i]
+
+			// update the triggered build with the latest status
+			t.Build = build
+
+			// create structured logger for the triggered build
+			logger := p.logger(t.Repo).WithFields(logrus.Fields{
+				"source_build": t.Source.GetNumber(),
+				"new_build":    build.GetNumber(),
+				"status":       build.GetStatus(),
+			})
+
+			if p.targeted(build.GetStatus()) {
+				logger.Info("build matched desired status")
+
+				successMap[t] = true
+			} else if strings.EqualFold(build.GetStatus(), constants.StatusPendingApproval) {
+				waiting = append(waiting, t.String())
+
+				// check if the build was already reported pending approval
+				if approvals[t] {
+					logger.Debug("build is still pending approval")
+
+					continue
+				}
+
+				approvals[t] = true
+
+				p.approve(ctx, t, logger)
+			} else if !completed(build.GetStatus()) {
+				logger.Debug("build has not completed")
+
+				continue
+			} else if p.retryable(t) {
+				// capture the Vela client for the repo
+				client, err := p.client(t.Repo.Profile)
+				if err != nil {
+					return err
+				}
+
+				err = p.retry(ctx, client, t)
+				if err != nil {
+					return p.timeout(ctx, err)
+				}
+
+				// record the retried build for the upstream build
+				p.checkpoint(triggered)
+			} else {
+				logger.WithField("attempts", t.attempts()).Error("build did not match desired status")
+
+				return fmt.Errorf("%w: triggered build %s/%d returned %s status after %d attempt(s), exiting",
+					ErrDownstreamFailed,
+					t.Repo.GetFullName(),
+					build.GetNumber(),
+					build.GetStatus(),
+					t.attempts(),
+				)
+			}
+		}
+
+		if len(successMap) == len(triggered) {
+			logrus.Info("all builds matched desired status")
+
+			return nil
+		}
+
+		logrus.Infof("sleeping for %s to check build statuses...", p.interval)
+
+		err = p.sleep(ctx)
+		if err != nil {
+			return awaiting(err, waiting)
+		}
+	}
+}
+
+// awaiting returns the provided error along with
+// the triggered builds still pending approval.
+func awaiting(err error, waiting []string) error {
+	// check if any builds are still pending approval
+	if len(waiting) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w, builds still pending approval: %s", err, strings.Join(waiting, ", "))
+}
+
+// approve approves the provided triggered build pending approval when
+// enabled for the plugin, otherwise it reports the build is waiting.
+//
+// A build that can not be approved is waited on until approved by
+// a repo admin or the build check timeout is reached.
+func (p *Plugin) approve(ctx context.Context, t *Triggered, logger *logrus.Entry) {
+	// check if approving builds is enabled
+	if !p.Build.Approve {
+		logger.Warn("build is pending approval, waiting for a repo admin to approve it")
+
+		return
+	}
+
+	logger.Info("approving build pending approval")
+
+	// capture the Vela client for the repo
+	client, err := p.client(t.Repo.Profile)
+	if err == nil {
+		err = client.ApproveBuild(ctx, t.Repo.GetOrg(), t.Repo.GetName(), t.Build.GetNumber())
+	}
+
+	if err != nil {
+		logger.WithError(err).Warn("unable to approve build, waiting for a repo admin to approve it")
+
+		return
+	}
+
+	logger.Info("build approved")
+}
+
+// retryable returns whether the provided triggered build
+// completed with a retry status and has attempts left.
+func (p *Plugin) retryable(t *Triggered) bool {
+	return contains(p.Build.RetryStatus, t.Build.GetStatus()) && t.attempts() <= p.Build.Retries
+}
+
+// retry restarts the provided triggered build
+// and records the attempt for the repo.
+func (p *Plugin) retry(ctx context.Context, client Client, t *Triggered) error {
+	logger := p.logger(t.Repo).WithFields(logrus.Fields{
+		"failed_build": t.Build.GetNumber(),
+		"status":       t.Build.GetStatus(),
+		"attempt":      t.attempts() + 1,
+		"max_attempts": p.Build.Retries + 1,
+	})
+
+	logger.Warn("retrying build")
+
+	// send API call to restart the failed build for the repo
+	b, err := client.RestartBuild(ctx, t.Repo.GetOrg(), t.Repo.GetName(), t.Build.GetNumber())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTrigger, err)
+	}
+
+	t.Attempts = t.attempts() + 1
+	t.Retried = append(t.Retried, t.Build)
+	t.Build = b
+
+	logger.WithField("new_build", b.GetNumber()).Info("new build created")
+
+	return nil
+}
+
+// report logs the outcome of every provided triggered build
+// along with the attempts it took for the repo.
+func (p *Plugin) report(triggered []*Triggered) {
+	for _, t := range triggered {
+		retried := []int64{}
+
+		for _, b := range t.Retried {
+			retried = append(retried, b.GetNumber())
+		}
+
+		p.logger(t.Repo).WithFields(logrus.Fields{
+			"build":    t.Build.GetNumber(),
+			"status":   t.Build.GetStatus(),
+			"attempts": t.attempts(),
+			"retried":  retried,
+		}).Info("downstream build outcome")
+	}
+}
+
+// Status captures the latest status of all the provided triggered builds and
+// writes a report of them to the provided writer. The build for each triggered
+// build is updated with the latest status.
+//
+// An error is returned when a completed build did not match the target statuses.
+func (p *Plugin) Status(ctx context.Context, triggered []*Triggered, w io.Writer) error {
+	logrus.Debug("checking build statuses of downstream builds...")
+
+	// create new list to store the failed builds
+	failed := []error{}
+
+	builds, err := p.poll(ctx, triggered)
+	if err != nil {
+		return err
+	}
+
+	for i, t := range triggered {
+		build := builds[i]
+
+		// update the triggered build with the latest status
+		t.Build = build
+
+		fmt.Fprintf(w, "%s: %s\n", t, build.GetStatus())
+
+		if completed(build.GetStatus()) && !p.targeted(build.GetStatus()) {
+			failed = append(failed, fmt.Errorf("%w: build %s returned %s status", ErrDownstreamFailed, t, build.GetStatus()))
+		}
+	}
+
+	return errors.Join(failed...)
+}
+
+// Cancel cancels all the provided triggered builds that have not completed.
+//
+// Every build is attempted before the errors for all of them are returned.
+// Builds that already completed are skipped. The build for each triggered
+// build is updated with the latest or canceled build.
+func (p *Plugin) Cancel(ctx context.Context, triggered []*Triggered) error {
+	// create new list to store the errors canceling builds
+	problems := []error{}
+
+	for _, t := range triggered {
+		// capture the Vela client for the repo
+		client, err := p.client(t.Repo.Profile)
+		if err != nil {
+			return err
+		}
+
+		logger := p.logger(t.Repo).WithField("build", t.Build.GetNumber())
+
+		// send API call to capture the latest state of the build
+		build, err := client.GetBuild(ctx, t.Repo.GetOrg(), t.Repo.GetName(), t.Build.GetNumber())
+		if err != nil {
+			logger.WithError(err).Error("unable to capture build")
+
+			problems = append(problems, err)
+
+			continue
+		}
+
+		// check if the build already completed
+		if completed(build.GetStatus()) {
+			// update the triggered build with the completed build
+			t.Build = build
+
+			logger.WithField("status", build.GetStatus()).Info("build already completed, skipping")
+
+			continue
+		}
+
+		logger.Info("canceling build")
+
+		build, err = client.CancelBuild(ctx, t.Repo.GetOrg(), t.Repo.GetName(), t.Build.GetNumber())
+		if err != nil {
+			logger.WithError(err).Error("unable to cancel build")
+
+			problems = append(problems, err)
+
+			continue
+		}
+
+		// update the triggered build with the canceled build
+		t.Build = build
+
+		logger.WithField("status", build.GetStatus()).Info("build canceled")
+	}
+
+	return errors.Join(problems...)
+}
+
+// completed returns whether the provided build status is final.
+func completed(status string) bool {
+	return !strings.EqualFold(status, constants.StatusRunning) &&
+		!strings.EqualFold(status, constants.StatusPending) &&
+		!strings.EqualFold(status, constants.StatusPendingApproval)
+}
+
+// targeted returns whether the provided status of a triggered build
+// matches the target statuses. A build that has not completed only
+// matches a target status provided without a negation or group.
+func (p *Plugin) targeted(status string) bool {
+	if !completed(status) {
+		return contains(p.Build.TargetStatus, status)
+	}
+
+	return matchStatus(p.Build.TargetStatus, status)
+}
+
+// sleep waits for the check interval of the plugin
+// or returns early when the context is done.
+func (p *Plugin) sleep(ctx context.Context) error {
+	timer := time.NewTimer(p.interval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return p.timeout(ctx, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// timeout returns the build check timeout error when the
+// provided context has expired, otherwise the provided error.
+func (p *Plugin) timeout(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w while awaiting downstream build statuses", ErrReportTimeout)
+	}
+
+	return err
+}
+
+// client returns the Vela client for the provided profile.
+//
+// Clients are created on first use and reused for
+// every repo sharing the same profile.
+func (p *Plugin) client(profile string) (Client, error) {
+	// check if a client was already created for the profile
+	client, ok := p.clients[profile]
+	if ok {
+		return client, nil
+	}
+
+	// capture the configuration for the profile
+	config, err := p.Config.Profile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// create new Vela client from profile configuration
+	client, err = NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.clients == nil {
+		p.clients = make(map[string]Client)
+	}
+
+	p.clients[profile] = client
+
+	return client, nil
+}
+
+// logger returns a log entry with the structured fields
+// identifying the provided repo for the plugin.
+func (p *Plugin) logger(repo *Target) *logrus.Entry {
+	fields := logrus.Fields{
+		"repo":   repo.GetFullName(),
+		"branch": repo.GetBranch(),
+		"event":  p.Build.Event,
+	}
+
+	// check if a profile is provided for the repo
+	if len(repo.Profile) > 0 {
+		fields["profile"] = repo.Profile
+	}
+
+	// check if a pull request is provided for the repo
+	if repo.PullRequest > 0 {
+		fields["pull_request"] = repo.PullRequest
+	}
+
+	// check if a schedule is provided for the builds
+	if len(p.Build.Schedule) > 0 {
+		fields["schedule"] = p.Build.Schedule
+	}
+
+	return logrus.WithFields(fields)
+}
+
+// Validate verifies the plugin is properly configured.
+func (p *Plugin) Validate() error {
+	logrus.Debug("validating plugin configuration")
+
+	// validate build configuration
+	err := p.Build.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	// validate config configuration
+	err = p.Config.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	// validate repo configuration
+	err = p.Repo.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	// validate state configuration
+	if p.State != nil {
+		err = p.State.Validate()
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrConfig, err)
+		}
+	}
+
+	// parse list of repos to verify their profiles
+	repos, err := p.Repo.Parse(p.Build.Branch)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	// iterate through each repo from provided configuration
+	for _, repo := range repos {
+		// verify the profile for the repo is provided
+		_, err = p.Config.Profile(repo.Profile)
+		if err != nil {
+			return fmt.Errorf("%w: invalid repo %s: %w", ErrConfig, repo.GetFullName(), err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateTriggered verifies the plugin is properly configured
+// for waiting on, reporting or canceling the provided builds.
+func (p *Plugin) ValidateTriggered(triggered []*Triggered) error {
+	logrus.Debug("validating plugin configuration")
+
+	// validate build configuration
+	err := p.Build.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	// validate config configuration
+	err = p.Config.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrConfig, err)
+	}
+
+	// verify builds are provided
+	if len(triggered) == 0 {
+		return fmt.Errorf("%w: no builds provided", ErrConfig)
+	}
+
+	// iterate through each provided build
+	for _, t := range triggered {
+		// verify the profile for the build is provided
+		_, err = p.Config.Profile(t.Repo.Profile)
+		if err != nil {
+			return fmt.Errorf("%w: invalid build %s: %w", ErrConfig, t, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,814 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/vela-downstream/downstream/velatest"
+)
+
+func TestDownstream_Plugin_Exec_Error(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Branch: "main",
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: "http://vela.localhost.com",
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world@main"},
+		},
+	}
+
+	err := p.Exec(context.Background())
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+}
+
+func TestDownstream_Plugin_Validate(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Branch: "main",
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: "http://vela.localhost.com",
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world@main"},
+		},
+	}
+
+	err := p.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+}
+
+func TestDownstream_Plugin_Validate_UnknownProfile(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Branch: "main",
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: "http://vela.localhost.com",
+			Token:  "superSecretVelaToken",
+			Profiles: map[string]*Profile{
+				"octocat": {Server: "http://octocat.localhost.com"},
+			},
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela:go-vela/hello-world@main"},
+		},
+	}
+
+	err := p.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Plugin_client(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Config: &Config{
+			Server: "http://vela.localhost.com",
+			Token:  "superSecretVelaToken",
+			Profiles: map[string]*Profile{
+				"octocat": {Server: "http://octocat.localhost.com"},
+			},
+		},
+	}
+
+	// run test
+	def, err := p.client("")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	got, err := p.client("octocat")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	if got == def {
+		t.Errorf("client for profile should not be the default client")
+	}
+
+	again, err := p.client("octocat")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	if again != got {
+		t.Errorf("client should be reused for the same profile")
+	}
+
+	_, err = p.client("foo")
+	if err == nil {
+		t.Errorf("client should have returned err")
+	}
+}
+
+func TestDownstream_Plugin_Validate_NoBuild(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{},
+		Config: &Config{
+			Server: "http://vela.localhost.com",
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world@main"},
+		},
+	}
+
+	err := p.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Plugin_Validate_NoConfig(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Branch: "main",
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world@main"},
+		},
+	}
+
+	err := p.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+
+	if !errors.Is(err, ErrConfig) {
+		t.Errorf("Validate returned err %v, want %v", err, ErrConfig)
+	}
+}
+
+func TestDownstream_Plugin_Validate_NoRepo(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Branch: "main",
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: "http://vela.localhost.com",
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{},
+	}
+
+	err := p.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Plugin_logger(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Event: constants.EventPush,
+		},
+	}
+
+	r := &Target{Repo: new(api.Repo), Profile: "octocat"}
+	r.SetFullName("go-vela/hello-world")
+	r.SetBranch("main")
+
+	want := logrus.Fields{
+		"repo":    "go-vela/hello-world",
+		"branch":  "main",
+		"event":   constants.EventPush,
+		"profile": "octocat",
+	}
+
+	// run test
+	got := p.logger(r)
+
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Errorf("logger is %v, want %v", got.Data, want)
+	}
+}
+
+func TestDownstream_Plugin_Exec(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusFailure)})
+	s.SetTransitions("go-vela/hello-world", constants.StatusRunning, constants.StatusSuccess)
+
+	// setup types
+	p, err := New(
+		WithBuild(&Build{
+			Branch:       "main",
+			Event:        constants.EventPush,
+			Status:       []string{constants.StatusSuccess},
+			Report:       true,
+			TargetStatus: []string{constants.StatusSuccess},
+			Timeout:      time.Minute,
+		}),
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+		WithRepos("go-vela/hello-world"),
+		WithInterval(time.Millisecond),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	// run test
+	err = p.Exec(context.Background())
+	if err != nil {
+		t.Errorf("Exec returned err: %v", err)
+	}
+
+	got := s.Build("go-vela/hello-world", 3)
+	if got.GetStatus() != constants.StatusSuccess {
+		t.Errorf("Exec restarted build is %v, want %s build 3", got, constants.StatusSuccess)
+	}
+}
+
+func TestDownstream_Plugin_Exec_Report(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name        string
+		transitions []string
+		timeout     time.Duration
+		want        error
+	}{
+		{
+			name:        "failure",
+			transitions: []string{constants.StatusRunning, constants.StatusFailure},
+			timeout:     time.Minute,
+			want:        ErrDownstreamFailed,
+		},
+		{
+			name:        "timeout",
+			transitions: []string{constants.StatusRunning},
+			timeout:     50 * time.Millisecond,
+			want:        ErrReportTimeout,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := velatest.NewServer()
+			defer s.Close()
+
+			s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+			s.SetTransitions("go-vela/hello-world", test.transitions...)
+
+			p, err := New(
+				WithBuild(&Build{
+					Branch:       "main",
+					Event:        constants.EventPush,
+					Status:       []string{constants.StatusSuccess},
+					Report:       true,
+					TargetStatus: []string{constants.StatusSuccess},
+					Timeout:      test.timeout,
+				}),
+				WithConfig(&Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+				}),
+				WithRepos("go-vela/hello-world"),
+				WithInterval(time.Millisecond),
+			)
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			err = p.Exec(context.Background())
+			if !errors.Is(err, test.want) {
+				t.Errorf("Exec returned err %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestDownstream_Plugin_Trigger(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-go")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+	s.AddBuild("go-vela/hello-go", &api.Build{Branch: vela.String("dev")})
+
+	// setup types
+	p, err := New(
+		WithBuild(&Build{
+			Branch: "main",
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		}),
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+		WithRepos("go-vela/hello-world", "go-vela/hello-go@dev"),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.Trigger(context.Background())
+	if err != nil {
+		t.Errorf("Trigger returned err: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Trigger returned %d builds, want 2", len(got))
+	}
+
+	for _, triggered := range got {
+		if triggered.Source.GetNumber() != 1 || triggered.Build.GetNumber() != 2 {
+			t.Errorf("Trigger for %s restarted build %d as %d, want 1 as 2", triggered.Repo.GetFullName(), triggered.Source.GetNumber(), triggered.Build.GetNumber())
+		}
+
+		if triggered.Build.GetStatus() != constants.StatusPending {
+			t.Errorf("Trigger for %s returned %s status, want %s", triggered.Repo.GetFullName(), triggered.Build.GetStatus(), constants.StatusPending)
+		}
+	}
+}
+
+func TestDownstream_Plugin_Status(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{})
+	s.AddBuild("go-vela/hello-world", &api.Build{Status: vela.String(constants.StatusRunning)})
+	s.AddBuild("go-vela/hello-world", &api.Build{Status: vela.String(constants.StatusFailure)})
+
+	buf := new(bytes.Buffer)
+
+	// setup types
+	p, err := New(
+		WithBuild(&Build{TargetStatus: []string{constants.StatusSuccess}}),
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	triggered, err := ParseTriggered("go-vela/hello-world/1", "go-vela/hello-world/2", "go-vela/hello-world/3")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	// run test
+	err = p.Status(context.Background(), triggered, buf)
+	if !errors.Is(err, ErrDownstreamFailed) {
+		t.Errorf("Status returned err %v, want %v", err, ErrDownstreamFailed)
+	}
+
+	want := "go-vela/hello-world/1: success\ngo-vela/hello-world/2: running\ngo-vela/hello-world/3: failure\n"
+	if buf.String() != want {
+		t.Errorf("Status output is %q, want %q", buf.String(), want)
+	}
+
+	if strings.Contains(err.Error(), "hello-world/2") {
+		t.Errorf("Status returned err %v, want no failure for running build", err)
+	}
+}
+
+func TestDownstream_Plugin_Cancel(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Status: vela.String(constants.StatusRunning)})
+	s.AddBuild("go-vela/hello-world", &api.Build{})
+	s.AddBuild("go-vela/hello-world", &api.Build{Status: vela.String(constants.StatusPending)})
+
+	// setup types
+	p, err := New(
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	triggered, err := ParseTriggered("go-vela/hello-world/1", "go-vela/hello-world/2", "go-vela/hello-world/3")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	// run test
+	err = p.Cancel(context.Background(), triggered)
+	if err != nil {
+		t.Errorf("Cancel returned err: %v", err)
+	}
+
+	if s.Calls("DELETE /api/v1/repos/{org}/{repo}/builds/{build}/cancel") != 2 {
+		t.Errorf("Cancel canceled %d builds, want 2", s.Calls("DELETE /api/v1/repos/{org}/{repo}/builds/{build}/cancel"))
+	}
+
+	if triggered[1].Build.GetStatus() != constants.StatusSuccess {
+		t.Errorf("Cancel build 2 is %s, want %s", triggered[1].Build.GetStatus(), constants.StatusSuccess)
+	}
+
+	for number, want := range map[int64]string{
+		1: constants.StatusCanceled,
+		2: constants.StatusSuccess,
+		3: constants.StatusCanceled,
+	} {
+		got := s.Build("go-vela/hello-world", number).GetStatus()
+		if got != want {
+			t.Errorf("Cancel build %d is %s, want %s", number, got, want)
+		}
+	}
+}
+
+func TestDownstream_Plugin_ValidateTriggered(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: "http://vela.localhost.com",
+			Token:  "superSecretVelaToken",
+		},
+		Repo: new(Repo),
+	}
+
+	triggered, err := ParseTriggered("go-vela/hello-world/1")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	// run test
+	err = p.ValidateTriggered(triggered)
+	if err != nil {
+		t.Errorf("ValidateTriggered returned err: %v", err)
+	}
+
+	err = p.ValidateTriggered(nil)
+	if !errors.Is(err, ErrConfig) {
+		t.Errorf("ValidateTriggered returned err %v, want %v", err, ErrConfig)
+	}
+
+	triggered, err = ParseTriggered("prod:go-vela/hello-world/1")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	err = p.ValidateTriggered(triggered)
+	if !errors.Is(err, ErrConfig) {
+		t.Errorf("ValidateTriggered returned err %v, want %v", err, ErrConfig)
+	}
+}
+
+func TestDownstream_Plugin_Trigger_Resume(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-go")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+	s.AddBuild("go-vela/hello-go", &api.Build{Branch: vela.String("main")})
+
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// record a build triggered for go-vela/hello-world by a previous run
+	previous, err := ParseTriggered("go-vela/hello-world/1")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	previous[0].Attempts = 2
+
+	err = (&State{Path: path, Key: "go-vela/upstream/7"}).Save(previous)
+	if err != nil {
+		t.Errorf("Save returned err: %v", err)
+	}
+
+	// setup types
+	p, err := New(
+		WithBuild(&Build{
+			Branch: "main",
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		}),
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+		WithRepos("go-vela/hello-world", "go-vela/hello-go"),
+		WithState(path, "go-vela/upstream/7"),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.Trigger(context.Background())
+	if err != nil {
+		t.Errorf("Trigger returned err: %v", err)
+	}
+
+	if len(got) != 2 || got[0].String() != "go-vela/hello-world/1" || got[1].String() != "go-vela/hello-go/2" {
+		t.Errorf("Trigger is %v, want resumed go-vela/hello-world/1 and triggered go-vela/hello-go/2", got)
+	}
+
+	if len(s.Builds("go-vela/hello-world")) != 1 {
+		t.Errorf("Trigger should not have restarted go-vela/hello-world")
+	}
+
+	if got[0].attempts() != 2 {
+		t.Errorf("Trigger resumed go-vela/hello-world with %d attempts, want 2", got[0].attempts())
+	}
+
+	// rerun to verify every build is resumed
+	got, err = p.Trigger(context.Background())
+	if err != nil {
+		t.Errorf("Trigger returned err: %v", err)
+	}
+
+	if len(got) != 2 || len(s.Builds("go-vela/hello-go")) != 2 {
+		t.Errorf("Trigger is %v, want both builds resumed", got)
+	}
+}
+
+func TestDownstream_Plugin_Wait_Retry(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name        string
+		queued      []string
+		transitions []string
+		want        error
+		attempts    int
+	}{
+		{
+			name:        "succeeds on retry",
+			queued:      []string{constants.StatusKilled},
+			transitions: []string{constants.StatusSuccess},
+			attempts:    2,
+		},
+		{
+			name:        "fails every attempt",
+			transitions: []string{constants.StatusError},
+			want:        ErrDownstreamFailed,
+			attempts:    3,
+		},
+		{
+			name:        "failure is not retried",
+			transitions: []string{constants.StatusFailure},
+			want:        ErrDownstreamFailed,
+			attempts:    1,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := velatest.NewServer()
+			defer s.Close()
+
+			s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+			s.SetTransitions("go-vela/hello-world", test.transitions...)
+
+			if len(test.queued) > 0 {
+				s.QueueTransitions("go-vela/hello-world", test.queued...)
+			}
+
+			p, err := New(
+				WithBuild(&Build{
+					Branch:       "main",
+					Event:        constants.EventPush,
+					Status:       []string{constants.StatusSuccess},
+					TargetStatus: []string{constants.StatusSuccess},
+					Timeout:      time.Minute,
+					Retries:      2,
+					RetryStatus:  []string{constants.StatusError, constants.StatusKilled},
+				}),
+				WithConfig(&Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+				}),
+				WithRepos("go-vela/hello-world"),
+				WithInterval(time.Millisecond),
+			)
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			triggered, err := p.Trigger(context.Background())
+			if err != nil {
+				t.Errorf("Trigger returned err: %v", err)
+			}
+
+			err = p.Wait(context.Background(), triggered)
+			if !errors.Is(err, test.want) {
+				t.Errorf("Wait returned err %v, want %v", err, test.want)
+			}
+
+			if triggered[0].Attempts != test.attempts || len(triggered[0].Retried) != test.attempts-1 {
+				t.Errorf("Wait attempts is %d with %d retried, want %d", triggered[0].Attempts, len(triggered[0].Retried), test.attempts)
+			}
+		})
+	}
+}
+
+func TestDownstream_Plugin_Trigger_DefaultBranch(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world"), Branch: vela.String("trunk")})
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-go")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("trunk")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("feature")})
+	s.AddBuild("go-vela/hello-go", &api.Build{Branch: vela.String("main")})
+	s.AddBuild("go-vela/hello-go", &api.Build{Branch: vela.String("dev")})
+
+	// setup types
+	p, err := New(
+		WithBuild(&Build{
+			DefaultBranch: true,
+			Event:         constants.EventPush,
+			Status:        []string{constants.StatusSuccess},
+		}),
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+		WithRepos("go-vela/hello-world", "go-vela/hello-go@dev"),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.Trigger(context.Background())
+	if err != nil {
+		t.Errorf("Trigger returned err: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Trigger returned %d builds, want 2", len(got))
+	}
+
+	if got[0].Repo.GetBranch() != "trunk" || got[0].Source.GetNumber() != 1 {
+		t.Errorf("Trigger for %s restarted build %d on %s, want build 1 on trunk", got[0].Repo.GetFullName(), got[0].Source.GetNumber(), got[0].Repo.GetBranch())
+	}
+
+	if got[1].Repo.GetBranch() != "dev" || got[1].Source.GetNumber() != 2 {
+		t.Errorf("Trigger for %s restarted build %d on %s, want build 2 on dev", got[1].Repo.GetFullName(), got[1].Source.GetNumber(), got[1].Repo.GetBranch())
+	}
+}
+
+func TestDownstream_Plugin_Trigger_DefaultBranch_NotFound(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	// setup types
+	p, err := New(
+		WithBuild(&Build{
+			DefaultBranch: true,
+			Event:         constants.EventPush,
+			Status:        []string{constants.StatusSuccess},
+		}),
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+		WithRepos("go-vela/missing"),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	// run test
+	_, err = p.Trigger(context.Background())
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Trigger returned err %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestDownstream_Plugin_Wait_PendingApproval(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		approve bool
+		want    error
+	}{
+		{
+			name:    "approved",
+			approve: true,
+		},
+		{
+			name: "not approved",
+			want: ErrReportTimeout,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := velatest.NewServer()
+			defer s.Close()
+
+			s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+			s.SetTransitions("go-vela/hello-world", constants.StatusPendingApproval, constants.StatusRunning, constants.StatusSuccess)
+
+			p, err := New(
+				WithBuild(&Build{
+					Branch:       "main",
+					Event:        constants.EventPush,
+					Status:       []string{constants.StatusSuccess},
+					TargetStatus: []string{constants.StatusSuccess},
+					Timeout:      50 * time.Millisecond,
+					Approve:      test.approve,
+				}),
+				WithConfig(&Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+				}),
+				WithRepos("go-vela/hello-world"),
+				WithInterval(time.Millisecond),
+			)
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			triggered, err := p.Trigger(context.Background())
+			if err != nil {
+				t.Errorf("Trigger returned err: %v", err)
+			}
+
+			err = p.Wait(context.Background(), triggered)
+			if !errors.Is(err, test.want) {
+				t.Errorf("Wait returned err %v, want %v", err, test.want)
+			}
+
+			if test.want != nil && !strings.Contains(err.Error(), "pending approval: go-vela/hello-world/2") {
+				t.Errorf("Wait returned err %v, want builds pending approval", err)
+			}
+
+			approvals := s.Calls("POST /api/v1/repos/{org}/{repo}/builds/{build}/approve")
+			if test.approve != (approvals == 1) {
+				t.Errorf("Wait approved build %d times, want approval %t", approvals, test.approve)
+			}
+		})
+	}
+}
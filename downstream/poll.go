@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+)
+
+// defaultConcurrency defines the default max number
+// of repos checked at the same time.
+const defaultConcurrency = 10
+
+// polled represents the triggered builds polled for a repo.
+type polled struct {
+	// Vela client for the repo
+	client Client
+	// repo the builds were triggered for
+	repo *Target
+	// indexes of the triggered builds for the repo
+	indexes []int
+}
+
+// poll captures the latest state of the provided triggered builds,
+// checking up to Build.Concurrency repos at the same time.
+//
+// The builds of a repo with several triggered builds, i.e. a repo
+// triggered for several branches, are captured from a single list
+// of the latest builds for the repo. Builds missing from the list
+// are captured one at a time.
+//
+// The builds are returned in the order of the triggered builds.
+func (p *Plugin) poll(ctx context.Context, triggered []*Triggered) ([]*api.Build, error) {
+	// capture the max number of repos to check at the same time
+	limit := p.Build.Concurrency
+	if limit <= 0 {
+		limit = defaultConcurrency
+	}
+
+	// group the triggered builds by the Vela client and repo
+	groups := []*polled{}
+	byRepo := make(map[string]*polled)
+
+	for i, t := range triggered {
+		key := t.Repo.Profile + "@" + t.Repo.GetFullName()
+
+		group, ok := byRepo[key]
+		if !ok {
+			// capture the Vela client up front since they are cached on the plugin
+			client, err := p.client(t.Repo.Profile)
+			if err != nil {
+				return nil, err
+			}
+
+			group = &polled{client: client, repo: t.Repo}
+
+			byRepo[key] = group
+			groups = append(groups, group)
+		}
+
+		group.indexes = append(group.indexes, i)
+	}
+
+	builds := make([]*api.Build, len(triggered))
+	errs := make([]error, len(groups))
+
+	// create semaphore to limit the repos checked at the same time
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+
+	for i, group := range groups {
+		sem <- struct{}{}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			errs[i] = group.poll(ctx, triggered, builds)
+		})
+	}
+
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return builds, nil
+}
+
+// poll captures the latest state of the triggered builds
+// for the repo into the provided builds.
+func (g *polled) poll(ctx context.Context, triggered []*Triggered, builds []*api.Build) error {
+	org, name := g.repo.GetOrg(), g.repo.GetName()
+
+	// check if the repo has several builds to capture
+	if len(g.indexes) > 1 {
+		// send API call to capture the latest builds for the repo
+		list, _, err := g.client.ListBuilds(ctx, org, name, &vela.BuildListOptions{
+			ListOptions: vela.ListOptions{Page: 1, PerPage: maxPerPage},
+		})
+		if err != nil {
+			return err
+		}
+
+		for j := range list {
+			for _, i := range g.indexes {
+				if list[j].GetNumber() == triggered[i].Build.GetNumber() {
+					builds[i] = &list[j]
+				}
+			}
+		}
+	}
+
+	errs := []error{}
+
+	for _, i := range g.indexes {
+		// skip builds already captured from the list
+		if builds[i] != nil {
+			continue
+		}
+
+		build, err := g.client.GetBuild(ctx, org, name, triggered[i].Build.GetNumber())
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		builds[i] = build
+	}
+
+	return errors.Join(errs...)
+}
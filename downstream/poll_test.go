@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/vela-downstream/downstream/velatest"
+)
+
+// limitClient represents a Vela client tracking the
+// max number of builds retrieved at the same time.
+type limitClient struct {
+	Client
+
+	mu       sync.Mutex
+	inflight int
+	max      int
+}
+
+// GetBuild tracks the builds retrieved at the same time
+// before capturing the build from the wrapped client.
+func (c *limitClient) GetBuild(ctx context.Context, org, repo string, number int64) (*api.Build, error) {
+	c.mu.Lock()
+	c.inflight++
+	c.max = max(c.max, c.inflight)
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.inflight--
+		c.mu.Unlock()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	return c.Client.GetBuild(ctx, org, repo, number)
+}
+
+func TestDownstream_Plugin_poll(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	names := []string{}
+
+	for i := range 12 {
+		repo := fmt.Sprintf("hello-world-%d", i)
+
+		s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String(repo)})
+		s.AddBuild("go-vela/"+repo, &api.Build{Number: vela.Int64(int64(i + 1))})
+
+		names = append(names, fmt.Sprintf("go-vela/%s/%d", repo, i+1))
+	}
+
+	config := &Config{
+		Server: s.URL,
+		Token:  "superSecretVelaToken",
+	}
+
+	sdk, err := NewClient(config)
+	if err != nil {
+		t.Errorf("NewClient returned err: %v", err)
+	}
+
+	client := &limitClient{Client: sdk}
+
+	// setup types
+	p, err := New(
+		WithBuild(&Build{Concurrency: 3}),
+		WithConfig(config),
+		WithClient(client),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	triggered, err := ParseTriggered(names...)
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.poll(context.Background(), triggered)
+	if err != nil {
+		t.Errorf("poll returned err: %v", err)
+	}
+
+	for i, b := range got {
+		if b.GetNumber() != int64(i+1) {
+			t.Errorf("poll build %d is %d, want %d", i, b.GetNumber(), i+1)
+		}
+	}
+
+	if client.max > 3 {
+		t.Errorf("poll checked %d repos at the same time, want at most 3", client.max)
+	}
+
+	if client.max < 2 {
+		t.Errorf("poll checked %d repos at the same time, want concurrent checks", client.max)
+	}
+}
+
+func TestDownstream_Plugin_poll_List(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-go")})
+	s.AddBuild("go-vela/hello-go", &api.Build{})
+
+	for range 105 {
+		s.AddBuild("go-vela/hello-world", &api.Build{})
+	}
+
+	// setup types
+	p, err := New(
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	triggered, err := ParseTriggered(
+		"go-vela/hello-world/105",
+		"go-vela/hello-go/1",
+		"go-vela/hello-world/2",
+		"go-vela/hello-world/104",
+	)
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	want := []string{
+		"go-vela/hello-world/105",
+		"go-vela/hello-go/1",
+		"go-vela/hello-world/2",
+		"go-vela/hello-world/104",
+	}
+
+	// run test
+	got, err := p.poll(context.Background(), triggered)
+	if err != nil {
+		t.Errorf("poll returned err: %v", err)
+	}
+
+	for i, b := range got {
+		name := fmt.Sprintf("%s/%d", b.GetRepo().GetFullName(), b.GetNumber())
+		if name != want[i] {
+			t.Errorf("poll build %d is %s, want %s", i, name, want[i])
+		}
+	}
+
+	lists := s.Calls("GET /api/v1/repos/{org}/{repo}/builds")
+	if lists != 1 {
+		t.Errorf("poll listed builds %d times, want 1", lists)
+	}
+
+	// the build missing from the list and the single build for
+	// the other repo are captured one at a time
+	gets := s.Calls("GET /api/v1/repos/{org}/{repo}/builds/{build}")
+	if gets != 2 {
+		t.Errorf("poll captured builds %d times, want 2", gets)
+	}
+}
+
+func TestDownstream_Plugin_poll_NotFound(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{})
+
+	// setup types
+	p, err := New(
+		WithConfig(&Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		}),
+	)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	triggered, err := ParseTriggered("go-vela/hello-world/1", "go-vela/hello-world/2")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	// run test
+	_, err = p.poll(context.Background(), triggered)
+	if err == nil {
+		t.Errorf("poll should have returned err")
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+// Preflight verifies every repo is able to be triggered and captures
+// the build to restart for each repo, before any build is restarted.
+//
+// All problems found are collected and returned together so a
+// single run reports everything that needs to be fixed.
+func (p *Plugin) Preflight(ctx context.Context, repos []*Target) (map[*Target]*api.Build, error) {
+	logrus.Debug("running preflight checks for downstream repos")
+
+	// create new map to store the builds to restart
+	found := make(map[*Target]*api.Build)
+
+	// create new list to store the problems found
+	problems := []error{}
+
+	// create new map to store the user verification by profile
+	users := make(map[string]error)
+
+	// iterate through each repo from provided configuration
+	for _, repo := range repos {
+		// create structured logger for the repo
+		logger := p.logger(repo)
+
+		// capture the Vela client for the repo
+		client, err := p.client(repo.Profile)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", repo.GetFullName(), err))
+
+			continue
+		}
+
+		// check if the repo should be verified
+		if p.Build.Preflight {
+			// verify the token user once per profile
+			err, ok := users[repo.Profile]
+			if !ok {
+				err = p.verifyUser(ctx, client)
+				if err != nil {
+					problems = append(problems, err)
+				}
+
+				users[repo.Profile] = err
+			}
+
+			// skip the remaining checks since every
+			// request with the token will fail
+			if err != nil {
+				continue
+			}
+
+			// verify the repo is able to be triggered
+			err = p.verifyRepo(ctx, client, repo)
+			if err != nil {
+				problems = append(problems, err)
+
+				continue
+			}
+		}
+
+		// capture the build to restart for the repo
+		build, err := p.search(ctx, client, repo)
+		if err != nil {
+			problems = append(problems, err)
+
+			continue
+		}
+
+		// check if we found a build to restart
+		if build == nil {
+			err = fmt.Errorf("%w: no %s build on branch %s with status %s found for %s",
+				ErrBuildNotFound,
+				p.Build.Event,
+				repo.GetBranch(),
+				p.Build.Status,
+				repo.GetFullName(),
+			)
+
+			// check if a schedule is provided for the builds
+			if len(p.Build.Schedule) > 0 {
+				err = fmt.Errorf("%w: no build for schedule %s with status %s found for %s",
+					ErrBuildNotFound,
+					p.Build.Schedule,
+					p.Build.Status,
+					repo.GetFullName(),
+				)
+			}
+
+			// check if a pull request is provided for the repo
+			if repo.PullRequest > 0 {
+				err = fmt.Errorf("%w: no build for pull request #%d with status %s found for %s",
+					ErrBuildNotFound,
+					repo.PullRequest,
+					p.Build.Status,
+					repo.GetFullName(),
+				)
+			}
+
+			if p.Build.Continue {
+				logger.Warn(err)
+
+				continue
+			}
+
+			problems = append(problems, err)
+
+			continue
+		}
+
+		found[repo] = build
+	}
+
+	// check if any problems were found
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("preflight found %d problem(s), no builds were restarted:\n%w", len(problems), errors.Join(problems...))
+	}
+
+	return found, nil
+}
+
+// verifyUser verifies the token for the client belongs to a Vela user.
+func (p *Plugin) verifyUser(ctx context.Context, client Client) error {
+	// send API call to capture the user for the token
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debugf("authenticated with Vela as user %s", user.GetName())
+
+	return nil
+}
+
+// verifyRepo verifies the repo exists and is active in Vela.
+//
+// The Vela API offers no check for write access without side effects,
+// so access is verified by the token user being able to read the repo.
+func (p *Plugin) verifyRepo(ctx context.Context, client Client, repo *Target) error {
+	// send API call to capture the repo
+	r, err := client.GetRepo(ctx, repo.GetOrg(), repo.GetName())
+	if err != nil {
+		return err
+	}
+
+	// verify the repo is active
+	if !r.GetActive() {
+		return fmt.Errorf("%s: %w (hint: %s)", repo.GetFullName(), ErrRepoInactive, hints[ErrRepoInactive])
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/vela-downstream/downstream/velatest"
+)
+
+// preflightServer creates a Vela server with an active go-vela/hello-world
+// repo, an inactive go-vela/inactive repo and no other repos.
+func preflightServer(t *testing.T) *velatest.Server {
+	t.Helper()
+
+	s := velatest.NewServer()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("inactive"), Active: vela.Bool(false)})
+
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+
+	return s
+}
+
+func TestDownstream_Plugin_Preflight(t *testing.T) {
+	// setup context
+	s := preflightServer(t)
+	defer s.Close()
+
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Branch:    "main",
+			Event:     constants.EventPush,
+			Status:    []string{constants.StatusSuccess},
+			Preflight: true,
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world"},
+		},
+	}
+
+	repos, err := p.Repo.Parse(p.Build.Branch)
+	if err != nil {
+		t.Errorf("Parse returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.Preflight(context.Background(), repos)
+	if err != nil {
+		t.Errorf("Preflight returned err: %v", err)
+	}
+
+	if got[repos[0]].GetNumber() != 1 {
+		t.Errorf("Preflight is %v, want build 1", got)
+	}
+}
+
+func TestDownstream_Plugin_Exec_PreflightProblems(t *testing.T) {
+	// setup context
+	s := preflightServer(t)
+	defer s.Close()
+
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Branch:    "main",
+			Event:     constants.EventPush,
+			Status:    []string{constants.StatusSuccess},
+			Preflight: true,
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{
+				"go-vela/hello-world",
+				"go-vela/inactive",
+				"go-vela/missing",
+			},
+		},
+	}
+
+	// run test
+	err := p.Exec(context.Background())
+	if err == nil {
+		t.Errorf("Exec should have returned err")
+	}
+
+	for _, repo := range []string{"go-vela/inactive", "go-vela/missing"} {
+		if !strings.Contains(err.Error(), repo) {
+			t.Errorf("Exec returned err %v, want problem for %s", err, repo)
+		}
+	}
+
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, ErrRepoInactive) {
+		t.Errorf("Exec returned err %v, want %v and %v", err, ErrNotFound, ErrRepoInactive)
+	}
+
+	if s.Calls("POST /api/v1/repos/{org}/{repo}/builds/{build}") > 0 {
+		t.Errorf("Exec should not have restarted any builds")
+	}
+}
+
+func TestDownstream_Plugin_Preflight_PullRequestNotFound(t *testing.T) {
+	// setup context
+	s := preflightServer(t)
+	defer s.Close()
+
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Event:     constants.EventPush,
+			Status:    []string{constants.StatusSuccess},
+			Preflight: true,
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world#9"},
+		},
+	}
+
+	repos, err := p.Repo.Parse(p.Build.Branch)
+	if err != nil {
+		t.Errorf("Parse returned err: %v", err)
+	}
+
+	// run test
+	_, err = p.Preflight(context.Background(), repos)
+	if !errors.Is(err, ErrBuildNotFound) {
+		t.Errorf("Preflight returned err %v, want %v", err, ErrBuildNotFound)
+	}
+
+	if !strings.Contains(err.Error(), "pull request #9") {
+		t.Errorf("Preflight returned err %v, want pull request #9", err)
+	}
+}
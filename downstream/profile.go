@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"fmt"
+	"os"
+)
+
+// Profile represents the plugin configuration for a named
+// Vela server and the credentials to authenticate with it.
+//
+// Any field not provided is inherited from the plugin Config, except
+// for the tokens of a profile on another Vela server, which must be
+// provided for the profile.
+type Profile struct {
+	// Vela server to interact with
+	Server string `json:"server,omitempty"`
+	// method to authenticate with the Vela server
+	AuthMethod string `json:"auth_method,omitempty"`
+	// token to authenticate with the Vela server
+	Token string `json:"token,omitempty"`
+	// environment variable containing the token
+	TokenEnv string `json:"token_env,omitempty"`
+	// refresh token to exchange for access tokens from the Vela server
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// environment variable containing the refresh token
+	RefreshTokenEnv string `json:"refresh_token_env,omitempty"`
+	// path to a CA bundle for verifying the Vela server certificate
+	CACert string `json:"ca_cert,omitempty"`
+	// path to a client certificate for authenticating with the Vela server
+	ClientCert string `json:"client_cert,omitempty"`
+	// path to a client key for authenticating with the Vela server
+	ClientKey string `json:"client_key,omitempty"`
+}
+
+// Config creates the Config for the profile by
+// overriding the provided Config with the profile.
+func (p *Profile) Config(c *Config) *Config {
+	// create a copy of the provided config
+	config := *c
+	config.Profiles = nil
+
+	// check if the profile is for another Vela server
+	if len(p.Server) > 0 && p.Server != c.Server {
+		// avoid sending the tokens for the plugin to another server
+		config.Token = ""
+		config.RefreshToken = ""
+	}
+
+	// override the fields provided for the profile
+	config.Server = override(config.Server, p.Server)
+	config.AuthMethod = override(config.AuthMethod, p.AuthMethod)
+	config.Token = override(config.Token, p.Token, os.Getenv(p.TokenEnv))
+	config.RefreshToken = override(config.RefreshToken, p.RefreshToken, os.Getenv(p.RefreshTokenEnv))
+	config.CACert = override(config.CACert, p.CACert)
+	config.ClientCert = override(config.ClientCert, p.ClientCert)
+	config.ClientKey = override(config.ClientKey, p.ClientKey)
+
+	return &config
+}
+
+// Profile returns the Config for the named profile. The plugin
+// Config is returned when no profile name is provided.
+func (c *Config) Profile(name string) (*Config, error) {
+	// check if a profile name is provided
+	if len(name) == 0 {
+		return c, nil
+	}
+
+	// capture the profile from the provided configuration
+	profile, ok := c.Profiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("no config profile %s provided", name)
+	}
+
+	return profile.Config(c), nil
+}
+
+// override returns the last non-empty value from the provided
+// overrides, falling back to the provided value if all are empty.
+func override(value string, overrides ...string) string {
+	for _, o := range overrides {
+		if len(o) > 0 {
+			value = o
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDownstream_Profile_Config(t *testing.T) {
+	// setup context
+	t.Setenv("OCTOCAT_TOKEN", "superSecretOctocatToken")
+
+	// setup types
+	c := &Config{
+		Server:     "http://vela.localhost.com",
+		AuthMethod: AuthPersonalAccessToken,
+		Token:      "superSecretVelaToken",
+		Depth:      50,
+		Profiles: map[string]*Profile{
+			"octocat": {
+				Server:   "http://octocat.localhost.com",
+				TokenEnv: "OCTOCAT_TOKEN",
+			},
+		},
+	}
+
+	want := &Config{
+		Server:     "http://octocat.localhost.com",
+		AuthMethod: AuthPersonalAccessToken,
+		Token:      "superSecretOctocatToken",
+		Depth:      50,
+	}
+
+	// run test
+	got := c.Profiles["octocat"].Config(c)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Config is %v, want %v", got, want)
+	}
+}
+
+func TestDownstream_Config_Profile(t *testing.T) {
+	// setup types
+	c := &Config{
+		Server: "http://vela.localhost.com",
+		Token:  "superSecretVelaToken",
+		Profiles: map[string]*Profile{
+			"octocat": {
+				Token: "superSecretOctocatToken",
+			},
+		},
+	}
+
+	// run test
+	got, err := c.Profile("")
+	if err != nil {
+		t.Errorf("Profile returned err: %v", err)
+	}
+
+	if got != c {
+		t.Errorf("Profile is %v, want %v", got, c)
+	}
+
+	got, err = c.Profile("octocat")
+	if err != nil {
+		t.Errorf("Profile returned err: %v", err)
+	}
+
+	if got.Server != c.Server || got.Token != "superSecretOctocatToken" {
+		t.Errorf("Profile is %v, want server %s and profile token", got, c.Server)
+	}
+
+	_, err = c.Profile("foo")
+	if err == nil {
+		t.Errorf("Profile should have returned err")
+	}
+}
+
+func TestDownstream_Config_Validate_InvalidProfile(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		profile *Profile
+	}{
+		{
+			name:    "invalid server",
+			profile: &Profile{Server: "octocat.localhost", Token: "superSecretOctocatToken"},
+		},
+		{
+			name:    "server without token",
+			profile: &Profile{Server: "http://octocat.localhost.com"},
+		},
+		{
+			name:    "server with empty token env",
+			profile: &Profile{Server: "http://octocat.localhost.com", TokenEnv: "OCTOCAT_TOKEN"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Config{
+				Server: "http://vela.localhost.com",
+				Token:  "superSecretVelaToken",
+				Profiles: map[string]*Profile{
+					"octocat": test.profile,
+				},
+			}
+
+			err := c.Validate()
+			if err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+		})
+	}
+}
+
+func TestDownstream_Profile_Config_NoInheritedToken(t *testing.T) {
+	// setup types
+	c := &Config{
+		Server:       "http://vela.localhost.com",
+		AuthMethod:   AuthRefreshToken,
+		Token:        "superSecretVelaToken",
+		RefreshToken: "superSecretRefreshToken",
+	}
+
+	// run test
+	got := (&Profile{Server: "http://octocat.localhost.com"}).Config(c)
+
+	if len(got.Token) > 0 || len(got.RefreshToken) > 0 {
+		t.Errorf("Config is %v, want no tokens for another server", got)
+	}
+
+	got = (&Profile{Server: c.Server}).Config(c)
+
+	if got.Token != c.Token || got.RefreshToken != c.RefreshToken {
+		t.Errorf("Config is %v, want tokens for the same server", got)
+	}
+}
@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// mask is the value used to replace secrets.
+	mask = "***"
+
+	// minSecretLength is the minimum length of a secret value masked,
+	// since masking shorter values would mangle every log line.
+	minSecretLength = 4
+)
+
+// Redactor represents the plugin configuration for masking
+// secret values from logs, errors and files.
+type Redactor struct {
+	// list of secret values to mask
+	secrets []string
+}
+
+// NewRedactor creates a Redactor that masks the provided secret values.
+func NewRedactor(secrets ...string) *Redactor {
+	r := new(Redactor)
+
+	// iterate through the secrets provided
+	for _, secret := range secrets {
+		// skip secrets with no value since
+		// masking them would mangle the text
+		if len(strings.TrimSpace(secret)) == 0 {
+			continue
+		}
+
+		// skip secrets too short to mask without mangling the text
+		if len(secret) < minSecretLength {
+			logrus.Warnf("skipping masking a secret value shorter than %d characters, it may appear in the output", minSecretLength)
+
+			continue
+		}
+
+		// capture the raw secret along with the encoded forms
+		// it could take when rendered in a log line or error
+		r.secrets = append(r.secrets, secret, url.QueryEscape(secret))
+
+		// capture the JSON escaped forms of the secret
+		// with and without HTML characters escaped
+		for _, html := range []bool{true, false} {
+			buf := new(bytes.Buffer)
+
+			enc := json.NewEncoder(buf)
+			enc.SetEscapeHTML(html)
+
+			err := enc.Encode(secret)
+			if err == nil {
+				r.secrets = append(r.secrets, strings.Trim(strings.TrimSpace(buf.String()), `"`))
+			}
+		}
+	}
+
+	// sort the secrets so the longest values are masked first
+	// to avoid leaving a partial secret behind when one
+	// secret is a substring of another secret
+	sort.SliceStable(r.secrets, func(i, j int) bool {
+		return len(r.secrets[i]) > len(r.secrets[j])
+	})
+
+	return r
+}
+
+// Redact masks all secret values found in the provided input string.
+func (r *Redactor) Redact(input string) string {
+	// iterate through the secrets to mask
+	for _, secret := range r.secrets {
+		input = strings.ReplaceAll(input, secret, mask)
+	}
+
+	return input
+}
+
+// Error masks all secret values found in the provided error.
+//
+// The returned error wraps the original error so it can
+// still be inspected with errors.Is and errors.As.
+func (r *Redactor) Error(err error) error {
+	// check if an error was provided
+	if err == nil {
+		return nil
+	}
+
+	// check if the error contains any secret values
+	msg := r.Redact(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{msg: msg, err: err}
+}
+
+// Formatter wraps the provided log formatter so every
+// formatted log entry has all secret values masked.
+func (r *Redactor) Formatter(f logrus.Formatter) logrus.Formatter {
+	return &redactedFormatter{formatter: f, redactor: r}
+}
+
+// Writer wraps the provided writer so all written content has
+// secret values masked. The content is masked a line at a time,
+// so a secret split across writes is still masked, and any content
+// after the last line is written when the writer is closed.
+func (r *Redactor) Writer(w io.Writer) io.WriteCloser {
+	return &redactedWriter{writer: w, redactor: r}
+}
+
+// redactedError represents an error with secret values masked.
+type redactedError struct {
+	msg string
+	err error
+}
+
+// Error returns the masked error message.
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactedFormatter represents a log formatter with secret values masked.
+type redactedFormatter struct {
+	formatter logrus.Formatter
+	redactor  *Redactor
+}
+
+// Format renders the log entry with secret values masked.
+func (f *redactedFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b, err := f.formatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(f.redactor.Redact(string(b))), nil
+}
+
+// redactedWriter represents a writer with secret values masked.
+type redactedWriter struct {
+	writer   io.Writer
+	redactor *Redactor
+	// content written after the last complete line
+	buf []byte
+}
+
+// Write writes the complete lines of the content with secret
+// values masked, holding any content after the last line.
+func (w *redactedWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	// check if a complete line was written
+	i := bytes.LastIndexByte(w.buf, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+
+	_, err := io.WriteString(w.writer, w.redactor.Redact(string(w.buf[:i+1])))
+
+	w.buf = w.buf[i+1:]
+
+	if err != nil {
+		return 0, err
+	}
+
+	// report the length of the original content
+	// to satisfy the io.Writer contract
+	return len(p), nil
+}
+
+// Close writes any content after the last complete line with
+// secret values masked. The underlying writer is not closed.
+func (w *redactedWriter) Close() error {
+	// check if any content is held
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(w.writer, w.redactor.Redact(string(w.buf)))
+
+	w.buf = nil
+
+	return err
+}
@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/server/constants"
+)
+
+func TestDownstream_Redactor_Redact(t *testing.T) {
+	// setup types
+	r := NewRedactor("superSecretVelaToken", "", "super")
+
+	want := "token *** and ***"
+
+	// run test
+	got := r.Redact("token superSecretVelaToken and super")
+
+	if got != want {
+		t.Errorf("Redact is %s, want %s", got, want)
+	}
+}
+
+func TestDownstream_Redactor_Redact_Encoded(t *testing.T) {
+	// setup types
+	secret := `super"Secret&Vela Token`
+
+	r := NewRedactor(secret)
+
+	// run test
+	got := r.Redact(`json: super\"Secret&Vela Token query: super%22Secret%26Vela+Token`)
+
+	if strings.Contains(got, "Secret") {
+		t.Errorf("Redact is %s, want no secret", got)
+	}
+}
+
+func TestDownstream_Redactor_Error(t *testing.T) {
+	// setup types
+	r := NewRedactor("superSecretVelaToken")
+
+	sentinel := errors.New("sentinel")
+
+	// run test
+	got := r.Error(fmt.Errorf("invalid token superSecretVelaToken: %w", sentinel))
+
+	if strings.Contains(got.Error(), "superSecretVelaToken") {
+		t.Errorf("Error is %v, want no secret", got)
+	}
+
+	if !errors.Is(got, sentinel) {
+		t.Errorf("Error is %v, want wrapped %v", got, sentinel)
+	}
+}
+
+func TestDownstream_Redactor_Error_Nil(t *testing.T) {
+	// setup types
+	r := NewRedactor("superSecretVelaToken")
+
+	// run test
+	err := r.Error(nil)
+	if err != nil {
+		t.Errorf("Error is %v, want nil", err)
+	}
+}
+
+func TestDownstream_Redactor_Writer(t *testing.T) {
+	// setup types
+	buf := new(bytes.Buffer)
+
+	r := NewRedactor("superSecretVelaToken")
+
+	// run test
+	w := r.Writer(buf)
+
+	n, err := w.Write([]byte("token: superSecretVelaToken"))
+	if err != nil {
+		t.Errorf("Write returned err: %v", err)
+	}
+
+	if n != len("token: superSecretVelaToken") {
+		t.Errorf("Write is %d, want %d", n, len("token: superSecretVelaToken"))
+	}
+
+	if buf.Len() > 0 {
+		t.Errorf("Write is %s, want partial line held", buf.String())
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Errorf("Close returned err: %v", err)
+	}
+
+	if buf.String() != "token: ***" {
+		t.Errorf("Write is %s, want token: ***", buf.String())
+	}
+}
+
+func TestDownstream_Redactor_Writer_Split(t *testing.T) {
+	// setup types
+	buf := new(bytes.Buffer)
+
+	r := NewRedactor("superSecretVelaToken")
+
+	// run test
+	w := r.Writer(buf)
+
+	for _, chunk := range []string{"token: superSecret", "VelaToken\nserver: ", "http://vela.localhost.com\n"} {
+		_, err := w.Write([]byte(chunk))
+		if err != nil {
+			t.Errorf("Write returned err: %v", err)
+		}
+	}
+
+	want := "token: ***\nserver: http://vela.localhost.com\n"
+
+	if buf.String() != want {
+		t.Errorf("Write is %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDownstream_Redactor_Redact_Short(t *testing.T) {
+	// setup types
+	r := NewRedactor("a", "superSecretVelaToken")
+
+	// run test
+	got := r.Redact("a token superSecretVelaToken")
+
+	if got != "a token ***" {
+		t.Errorf("Redact is %s, want a token ***", got)
+	}
+}
+
+func TestDownstream_Redactor_Formatter_Trace(t *testing.T) {
+	// setup context
+	buf := new(bytes.Buffer)
+
+	out := logrus.StandardLogger().Out
+	formatter := logrus.StandardLogger().Formatter
+	level := logrus.GetLevel()
+
+	defer func() {
+		logrus.SetOutput(out)
+		logrus.SetFormatter(formatter)
+		logrus.SetLevel(level)
+	}()
+
+	// setup server that echoes the token back in the error
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+
+		fmt.Fprintf(w, `{"error": "invalid token %s"}`, r.Header.Get("Token"))
+	}))
+	defer s.Close()
+
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			Branch: "main",
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+		Repo: &Repo{
+			Names: []string{"go-vela/hello-world@main"},
+		},
+	}
+
+	r := NewRedactor(p.Config.Token)
+
+	for _, f := range []logrus.Formatter{new(logrus.TextFormatter), new(logrus.JSONFormatter)} {
+		buf.Reset()
+
+		logrus.SetOutput(buf)
+		logrus.SetFormatter(r.Formatter(f))
+		logrus.SetLevel(logrus.TraceLevel)
+
+		// run test
+		err := r.Error(p.Exec(context.Background()))
+		if err == nil {
+			t.Errorf("Exec should have returned err")
+		}
+
+		logrus.WithError(err).Tracef("plugin configured with token %s", p.Config.Token)
+
+		if strings.Contains(err.Error(), p.Config.Token) {
+			t.Errorf("Exec returned err %v, want no secret", err)
+		}
+
+		if strings.Contains(buf.String(), p.Config.Token) {
+			t.Errorf("log output is %s, want no secret", buf.String())
+		}
+	}
+}
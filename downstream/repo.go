@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+// Repo represents the plugin configuration for Repo information.
+type Repo struct {
+	// list of Vela repos to trigger a build for
+	//
+	// each repo may be prefixed with the name of a
+	// config profile, i.e. <profile>:<org>/<repo>
+	//
+	// each repo may be suffixed with a branch, i.e. <org>/<repo>@<branch>,
+	// or with a pull request number, i.e. <org>/<repo>#<number>
+	Names []string
+}
+
+// Target represents a parsed repo to trigger a build for.
+type Target struct {
+	*api.Repo
+	// name of the profile for the Vela server and credentials
+	Profile string
+	// number of the pull request to trigger a build for
+	PullRequest int
+}
+
+// Parse verifies the Repo is properly configured.
+func (r *Repo) Parse(branch string) ([]*Target, error) {
+	logrus.Trace("parsing repos from provided configuration")
+
+	// create new repos type to store parsed repos
+	repos := []*Target{}
+
+	for _, name := range r.Names {
+		logrus.Tracef("parsing repo %s", name)
+
+		// create new repo type to store parsed repo information
+		repo := &Target{Repo: new(api.Repo)}
+
+		// check if a branch was provided with org/repo@branch
+		//
+		// the branch is split first since it may contain
+		// slashes, i.e. org/repo@release/* as input
+		fullName, ref, hasBranch := strings.Cut(name, "@")
+
+		// check if a pull request was provided with org/repo#number
+		fullName, number, hasPullRequest := strings.Cut(fullName, "#")
+		if hasPullRequest {
+			// verify a branch was not provided with the pull request
+			if hasBranch {
+				return nil, fmt.Errorf("unable to parse repo with both a branch and pull request: %s", name)
+			}
+
+			pr, err := strconv.Atoi(number)
+			if err != nil || pr <= 0 {
+				return nil, fmt.Errorf("unable to parse pull request number for repo: %s", name)
+			}
+
+			repo.PullRequest = pr
+		}
+
+		// check if a profile was provided with profile:org/repo
+		profile, rest, ok := strings.Cut(fullName, ":")
+		if ok {
+			repo.Profile = profile
+			fullName = rest
+		}
+
+		// split the repo on / to account for org/repo as input
+		parts := strings.Split(fullName, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unable to parse repo on /: %s", name)
+		}
+
+		// set the org field for the repo
+		repo.SetOrg(parts[0])
+		// set the name field for the repo
+		repo.SetName(parts[1])
+
+		if hasBranch {
+			// verify the remaining branch has no @ to account for repo@branch as input
+			if strings.Contains(ref, "@") {
+				return nil, fmt.Errorf("unable to parse repo on @: %s", name)
+			}
+
+			repo.SetBranch(ref)
+		}
+
+		// check if a branch was parsed from the input
+		//
+		// builds for a pull request are searched on any branch
+		if len(repo.GetBranch()) == 0 && repo.PullRequest == 0 {
+			// set the default branch from the provided input
+			repo.SetBranch(branch)
+		}
+
+		// set the full name for the repo
+		repo.SetFullName(
+			fmt.Sprintf("%s/%s", repo.GetOrg(), repo.GetName()),
+		)
+
+		// add the parsed repo to our list of repos
+		repos = append(repos, repo)
+	}
+
+	return repos, nil
+}
+
+// Validate verifies the Repo is properly configured.
+func (r *Repo) Validate() error {
+	logrus.Trace("validating repo configuration")
+
+	// verify repo names are provided
+	if len(r.Names) == 0 {
+		return fmt.Errorf("no repo names provided")
+	}
+
+	// iterate through all provided repo names
+	for _, repo := range r.Names {
+		// split the branch from the repo name, i.e. org/repo@release/*
+		name, branch, hasBranch := strings.Cut(repo, "@")
+
+		// split the pull request from the repo name, i.e. org/repo#123
+		name, number, hasPullRequest := strings.Cut(name, "#")
+		if hasPullRequest {
+			// check if a branch is provided with the pull request
+			if hasBranch {
+				return fmt.Errorf("invalid <org>/<repo>#<number> name provided with a branch: %s", repo)
+			}
+
+			// check if the pull request number is valid
+			pr, err := strconv.Atoi(number)
+			if err != nil || pr <= 0 {
+				return fmt.Errorf("invalid pull request number provided for %s", repo)
+			}
+		}
+
+		// check if the repo name has an empty profile
+		if strings.HasPrefix(name, ":") || strings.Count(name, ":") > 1 {
+			return fmt.Errorf("invalid <profile>:<org>/<repo> name provided: %s", repo)
+		}
+
+		// check if the repo name has at least one slash
+		if !strings.Contains(name, "/") {
+			return fmt.Errorf("invalid <org>/<repo> name provided: %s", repo)
+		}
+
+		// check if the repo name has a more than one slash
+		if strings.Count(name, "/") > 1 {
+			return fmt.Errorf("invalid <org>/<repo> name provided: %s", repo)
+		}
+
+		// check if the branch pattern is valid
+		_, err := branchMatcher(branch)
+		if err != nil {
+			return fmt.Errorf("invalid branch provided for %s: %w", repo, err)
+		}
+	}
+
+	return nil
+}
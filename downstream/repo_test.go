@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+func TestDownstream_Repo_Parse(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela/hello-world@test", "go-vela/hello-world", "octocat:go-vela/hello-world@test"},
+	}
+
+	r1 := new(api.Repo)
+	r1.SetOrg("go-vela")
+	r1.SetName("hello-world")
+	r1.SetFullName("go-vela/hello-world")
+	r1.SetBranch("test")
+
+	r2 := new(api.Repo)
+	r2.SetOrg("go-vela")
+	r2.SetName("hello-world")
+	r2.SetFullName("go-vela/hello-world")
+	r2.SetBranch("main")
+
+	want := []*Target{{Repo: r1}, {Repo: r2}, {Repo: r1, Profile: "octocat"}}
+
+	// run test
+	got, err := r.Parse("main")
+	if err != nil {
+		t.Errorf("Parse returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse is %v, want %v", got, want)
+	}
+}
+
+func TestDownstream_Repo_Parse_MultipleSlashes(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela/hello-world/main"},
+	}
+
+	// run test
+	got, err := r.Parse("main")
+	if err == nil {
+		t.Errorf("Parse should have returned err")
+	}
+
+	if got != nil {
+		t.Errorf("Parse is %v, want nil", got)
+	}
+}
+
+func TestDownstream_Repo_Parse_MultipleAtSigns(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela/hello-world@main@"},
+	}
+
+	// run test
+	got, err := r.Parse("main")
+	if err == nil {
+		t.Errorf("Parse should have returned err")
+	}
+
+	if got != nil {
+		t.Errorf("Parse is %v, want nil", got)
+	}
+}
+
+func TestDownstream_Repo_Validate(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela/hello-world@main"},
+	}
+
+	err := r.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+}
+
+func TestDownstream_Repo_Validate_EmptyProfile(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{":go-vela/hello-world"},
+	}
+
+	// run test
+	err := r.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Repo_Validate_NoNames(t *testing.T) {
+	// setup types
+	r := &Repo{}
+
+	// run test
+	err := r.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Repo_Validate_NoSlash(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela_hello-world"},
+	}
+
+	// run test
+	err := r.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Repo_Validate_MultipleSlashes(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela/hello-world/main"},
+	}
+
+	// run test
+	err := r.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Repo_Parse_BranchPattern(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela/hello-world@release/*", "octocat:go-vela/hello-world@feature/login"},
+	}
+
+	// run test
+	got, err := r.Parse("main")
+	if err != nil {
+		t.Errorf("Parse returned err: %v", err)
+	}
+
+	if got[0].GetFullName() != "go-vela/hello-world" || got[0].GetBranch() != "release/*" {
+		t.Errorf("Parse is %s@%s, want go-vela/hello-world@release/*", got[0].GetFullName(), got[0].GetBranch())
+	}
+
+	if got[1].Profile != "octocat" || got[1].GetBranch() != "feature/login" {
+		t.Errorf("Parse is %s:%s@%s, want octocat:go-vela/hello-world@feature/login", got[1].Profile, got[1].GetFullName(), got[1].GetBranch())
+	}
+}
+
+func TestDownstream_Repo_Validate_BranchPattern(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela/hello-world@release/*", `go-vela/hello-world@/^release-\d+$/`},
+	}
+
+	// run test
+	err := r.Validate()
+	if err != nil {
+		t.Errorf("Validate returned err: %v", err)
+	}
+
+	r.Names = []string{"go-vela/hello-world@release/["}
+
+	err = r.Validate()
+	if err == nil {
+		t.Errorf("Validate should have returned err")
+	}
+}
+
+func TestDownstream_Repo_Parse_PullRequest(t *testing.T) {
+	// setup types
+	r := &Repo{
+		Names: []string{"go-vela/hello-world#123", "octocat:go-vela/hello-world#7"},
+	}
+
+	// run test
+	got, err := r.Parse("main")
+	if err != nil {
+		t.Errorf("Parse returned err: %v", err)
+	}
+
+	if got[0].GetFullName() != "go-vela/hello-world" || got[0].PullRequest != 123 || len(got[0].GetBranch()) > 0 {
+		t.Errorf("Parse is %s#%d@%s, want go-vela/hello-world#123", got[0].GetFullName(), got[0].PullRequest, got[0].GetBranch())
+	}
+
+	if got[1].Profile != "octocat" || got[1].PullRequest != 7 {
+		t.Errorf("Parse is %s:%s#%d, want octocat:go-vela/hello-world#7", got[1].Profile, got[1].GetFullName(), got[1].PullRequest)
+	}
+}
+
+func TestDownstream_Repo_Validate_PullRequest(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+	}{
+		{name: "go-vela/hello-world#123"},
+		{name: "octocat:go-vela/hello-world#123"},
+		{name: "go-vela/hello-world#", failure: true},
+		{name: "go-vela/hello-world#0", failure: true},
+		{name: "go-vela/hello-world#abc", failure: true},
+		{name: "go-vela/hello-world#123@main", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Repo{
+				Names: []string{test.name},
+			}
+
+			err := r.Validate()
+			if test.failure != (err != nil) {
+				t.Errorf("Validate returned err %v, want failure %t", err, test.failure)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+)
+
+const (
+	// defaultDepth defines the default max number
+	// of builds searched for the repo.
+	defaultDepth = 50
+
+	// maxPerPage defines the max number of builds
+	// the Vela server returns for a single page.
+	maxPerPage = 100
+)
+
+// search captures the build to restart for the provided repo.
+//
+// The builds for the repo are listed newest first and up to
+// Config.Depth builds are searched. The search stops at the
+// first matching build unless a later build may be preferred.
+//
+// A nil build is returned when no build matches the configuration.
+func (p *Plugin) search(ctx context.Context, client Client, repo *Target) (*api.Build, error) {
+	// create new build type to store last successful build
+	build := api.Build{}
+
+	// capture the max number of builds to search
+	depth := p.Config.Depth
+	if depth <= 0 {
+		depth = defaultDepth
+	}
+
+	// create structured logger for the repo
+	logger := p.logger(repo)
+
+	logger.Infof("searching last %d builds", depth)
+
+	// capture the matcher for a branch pattern, i.e. release/*
+	match, err := branchMatcher(repo.GetBranch())
+	if err != nil {
+		return nil, err
+	}
+
+	// capture the filters for the build
+	f, err := p.Build.filter(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// only the first matching build can be selected unless
+	// searching for the oldest or highest version build
+	first := p.Build.Order != OrderOldest && (match == nil || p.Build.BranchOrder != BranchOrderSemver)
+
+	// capture the options for listing builds
+	opts, err := p.listOptions(ctx, client, repo, match != nil, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// set the per page for options to fit the depth
+	opts.PerPage = min(depth, maxPerPage)
+
+	// track the number of builds searched
+	searched := 0
+
+search:
+	for searched < depth {
+		// send API call to capture a list of builds for the repo
+		builds, next, err := client.ListBuilds(ctx, repo.GetOrg(), repo.GetName(), opts)
+		if err != nil {
+			return nil, err
+		}
+
+		// iterate through list of builds for the repo
+		for _, b := range builds {
+			// stop searching after the max number of builds
+			if searched == depth {
+				break search
+			}
+
+			searched++
+
+			// check if the build branch matches the branch pattern
+			if match != nil && !match(b.GetBranch()) {
+				continue
+			}
+
+			// check if the build matches the filters
+			if !f.match(&b) {
+				continue
+			}
+
+			// check if the build branch, event and status match
+			if matchStatus(p.Build.Status, b.GetStatus()) {
+				// check if the build is preferred over the current build
+				if p.prefer(&b, &build, match != nil) {
+					// update the build object to the current build
+					build = b
+				}
+
+				// stop searching when no later build can be preferred
+				if first {
+					break search
+				}
+			}
+		}
+
+		// break the loop if there is no more results to page through
+		if next == 0 {
+			break
+		}
+
+		// update the options for listing builds
+		// to point at the next page
+		opts.ListOptions.Page = next
+	}
+
+	logger.Debugf("searched %d builds", searched)
+
+	// check if we found a build to restart
+	if build.GetNumber() == 0 {
+		return nil, nil
+	}
+
+	logger.WithFields(logrus.Fields{
+		"source_build": build.GetNumber(),
+		"build_branch": build.GetBranch(),
+		"status":       build.GetStatus(),
+	}).Info("found build")
+
+	return &build, nil
+}
+
+// listOptions returns the options for listing the builds to search for the
+// provided repo, updating the filters for the builds the server can not filter.
+func (p *Plugin) listOptions(ctx context.Context, client Client, repo *Target, pattern bool, f *filter) (*vela.BuildListOptions, error) {
+	// only filter literal branches with the server
+	branch := repo.GetBranch()
+	if pattern {
+		branch = ""
+	}
+
+	// only filter the event without the action with the server
+	event, _ := p.Build.event()
+
+	// check if a pull request is provided for the repo
+	if repo.PullRequest > 0 {
+		f.pullRequest = repo.PullRequest
+
+		// search pull request builds unless searching comment builds
+		if event != constants.EventPull && event != constants.EventComment {
+			event = constants.EventPull
+			f.action = ""
+		}
+	}
+
+	// check if a schedule is provided for the builds
+	if len(p.Build.Schedule) > 0 {
+		// send API call to capture the schedule for the repo
+		schedule, err := client.GetSchedule(ctx, repo.GetOrg(), repo.GetName(), p.Build.Schedule)
+		if err != nil {
+			return nil, err
+		}
+
+		// check if the schedule is active
+		if !schedule.GetActive() {
+			p.logger(repo).WithField("schedule", schedule.GetName()).Warn("schedule is inactive, restarting its latest build anyway")
+		}
+
+		// search the builds triggered by the schedule
+		event = constants.EventSchedule
+		f.action = ""
+		f.schedule = schedule.GetName()
+
+		// search the branch of the schedule without a branch
+		if len(branch) == 0 && !pattern {
+			branch = schedule.GetBranch()
+		}
+	}
+
+	// only filter a single status with the server
+	status := serverStatus(p.Build.Status)
+
+	// create options for listing builds
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildListOptions
+	return &vela.BuildListOptions{
+		Branch: branch,
+		Event:  event,
+		Status: status,
+		Before: f.before,
+		After:  f.after,
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#ListOptions
+		ListOptions: vela.ListOptions{
+			// set the default starting page for options
+			Page: 1,
+		},
+	}, nil
+}
+
+// prefer returns whether the provided build matching the configuration
+// should replace the current build, given builds are listed newest first.
+func (p *Plugin) prefer(b, current *api.Build, pattern bool) bool {
+	// check if a build has been found yet
+	if current.GetNumber() == 0 {
+		return true
+	}
+
+	// check if the build is from a higher or lower version branch
+	if pattern && p.Build.BranchOrder == BranchOrderSemver {
+		if higherBranch(b.GetBranch(), current.GetBranch()) {
+			return true
+		}
+
+		if higherBranch(current.GetBranch(), b.GetBranch()) {
+			return false
+		}
+	}
+
+	// an older build is only preferred for the oldest order
+	return p.Build.Order == OrderOldest
+}
@@ -0,0 +1,629 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/vela-downstream/downstream/velatest"
+)
+
+func TestDownstream_Plugin_search(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("dev")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusFailure)})
+
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+	}
+
+	client, err := p.client("")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+	if err != nil {
+		t.Errorf("search returned err: %v", err)
+	}
+
+	if got.GetNumber() != 2 {
+		t.Errorf("search is build %d, want 2", got.GetNumber())
+	}
+}
+
+func TestDownstream_Plugin_search_Pagination(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+
+	for range 150 {
+		s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusFailure)})
+	}
+
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess, constants.StatusCanceled},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+			Depth:  200,
+		},
+	}
+
+	client, err := p.client("")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+	if err != nil {
+		t.Errorf("search returned err: %v", err)
+	}
+
+	if got.GetNumber() != 1 {
+		t.Errorf("search is build %d, want 1", got.GetNumber())
+	}
+
+	if s.Calls("GET /api/v1/repos/{org}/{repo}/builds") != 2 {
+		t.Errorf("search listed builds %d times, want 2", s.Calls("GET /api/v1/repos/{org}/{repo}/builds"))
+	}
+}
+
+func TestDownstream_Plugin_search_FirstMatch(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+
+	for range 150 {
+		s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+	}
+
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+			Depth:  500,
+		},
+	}
+
+	client, err := p.client("")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+	if err != nil {
+		t.Errorf("search returned err: %v", err)
+	}
+
+	if got.GetNumber() != 150 {
+		t.Errorf("search is build %d, want newest build 150", got.GetNumber())
+	}
+
+	if s.Calls("GET /api/v1/repos/{org}/{repo}/builds") != 1 {
+		t.Errorf("search listed builds %d times, want 1", s.Calls("GET /api/v1/repos/{org}/{repo}/builds"))
+	}
+}
+
+func TestDownstream_Plugin_search_Depth(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		status []string
+		want   int64
+	}{
+		{
+			// the server only returns successful builds
+			name:   "server status filter",
+			status: []string{constants.StatusSuccess},
+			want:   1,
+		},
+		{
+			// the failed builds count towards the depth
+			name:   "client status filter",
+			status: []string{constants.StatusSuccess, constants.StatusCanceled},
+			want:   0,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := velatest.NewServer()
+			defer s.Close()
+
+			s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+
+			for range 20 {
+				s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusFailure)})
+			}
+
+			p := &Plugin{
+				Build: &Build{
+					Event:  constants.EventPush,
+					Status: test.status,
+				},
+				Config: &Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+					Depth:  5,
+				},
+			}
+
+			client, err := p.client("")
+			if err != nil {
+				t.Errorf("client returned err: %v", err)
+			}
+
+			got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+			if err != nil {
+				t.Errorf("search returned err: %v", err)
+			}
+
+			if got.GetNumber() != test.want {
+				t.Errorf("search is build %d, want %d", got.GetNumber(), test.want)
+			}
+
+			if s.Calls("GET /api/v1/repos/{org}/{repo}/builds") != 1 {
+				t.Errorf("search listed builds %d times, want 1", s.Calls("GET /api/v1/repos/{org}/{repo}/builds"))
+			}
+		})
+	}
+}
+
+func TestDownstream_Plugin_search_NotFound(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusFailure)})
+
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+	}
+
+	client, err := p.client("")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+	if err != nil {
+		t.Errorf("search returned err: %v", err)
+	}
+
+	if got != nil {
+		t.Errorf("search is %v, want nil", got)
+	}
+}
+
+func TestDownstream_Plugin_search_BranchPattern(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name  string
+		order string
+		want  int64
+	}{
+		{
+			name:  "newest",
+			order: BranchOrderNewest,
+			want:  4,
+		},
+		{
+			name:  "semver",
+			order: BranchOrderSemver,
+			want:  3,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := velatest.NewServer()
+			defer s.Close()
+
+			s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("release/1.2.0")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("release/1.10.0")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("release/1.10.0")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("release/1.9.0")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("release/2.0.0"), Status: vela.String(constants.StatusFailure)})
+
+			p := &Plugin{
+				Build: &Build{
+					Event:       constants.EventPush,
+					Status:      []string{constants.StatusSuccess},
+					BranchOrder: test.order,
+				},
+				Config: &Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+				},
+			}
+
+			client, err := p.client("")
+			if err != nil {
+				t.Errorf("client returned err: %v", err)
+			}
+
+			repo := &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()}
+			repo.SetBranch("release/*")
+
+			got, err := p.search(context.Background(), client, repo)
+			if err != nil {
+				t.Errorf("search returned err: %v", err)
+			}
+
+			if got.GetNumber() != test.want {
+				t.Errorf("search is build %d on %s, want %d", got.GetNumber(), got.GetBranch(), test.want)
+			}
+		})
+	}
+}
+
+func TestDownstream_Plugin_search_Filters(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name  string
+		build *Build
+		want  int64
+	}{
+		{
+			name:  "newest",
+			build: &Build{},
+			want:  4,
+		},
+		{
+			name:  "oldest",
+			build: &Build{Order: OrderOldest},
+			want:  1,
+		},
+		{
+			name:  "max age",
+			build: &Build{MaxAge: 24 * time.Hour, Order: OrderOldest},
+			want:  3,
+		},
+		{
+			name:  "created before",
+			build: &Build{CreatedBefore: time.Now().Add(-24 * time.Hour)},
+			want:  2,
+		},
+		{
+			name:  "author",
+			build: &Build{Author: "hubot"},
+			want:  2,
+		},
+		{
+			name:  "sender and message",
+			build: &Build{Sender: "vela-bot", Message: "^chore"},
+			want:  3,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := velatest.NewServer()
+			defer s.Close()
+
+			now := time.Now()
+
+			s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+			s.AddBuild("go-vela/hello-world", filterBuild(now.Add(-90*24*time.Hour), "octocat", "octocat", "feat: initial"))
+			s.AddBuild("go-vela/hello-world", filterBuild(now.Add(-30*24*time.Hour), "hubot", "octocat", "fix: typo"))
+			s.AddBuild("go-vela/hello-world", filterBuild(now.Add(-time.Hour), "octocat", "vela-bot", "chore: release"))
+			s.AddBuild("go-vela/hello-world", filterBuild(now.Add(-time.Minute), "octocat", "octocat", "feat: search"))
+
+			p := &Plugin{
+				Build: test.build,
+				Config: &Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+				},
+			}
+
+			p.Build.Event = constants.EventPush
+			p.Build.Status = []string{constants.StatusSuccess}
+
+			client, err := p.client("")
+			if err != nil {
+				t.Errorf("client returned err: %v", err)
+			}
+
+			got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+			if err != nil {
+				t.Errorf("search returned err: %v", err)
+			}
+
+			if got.GetNumber() != test.want {
+				t.Errorf("search is build %d, want %d", got.GetNumber(), test.want)
+			}
+		})
+	}
+}
+
+func TestDownstream_Plugin_search_EventAction(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Event: vela.String(constants.EventPull), EventAction: vela.String(constants.ActionOpened)})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Event: vela.String(constants.EventPull), EventAction: vela.String(constants.ActionSynchronize)})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Event: vela.String(constants.EventComment), EventAction: vela.String(constants.ActionOpened)})
+
+	p := &Plugin{
+		Build: &Build{
+			Event:  "pull_request:opened",
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+	}
+
+	client, err := p.client("")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+	if err != nil {
+		t.Errorf("search returned err: %v", err)
+	}
+
+	if got.GetNumber() != 1 {
+		t.Errorf("search is build %d, want 1", got.GetNumber())
+	}
+}
+
+func TestDownstream_Plugin_search_PullRequest(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Event: vela.String(constants.EventPull), Ref: vela.String("refs/pull/123/head")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Event: vela.String(constants.EventPull), Ref: vela.String("refs/pull/123/head")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Event: vela.String(constants.EventPull), Ref: vela.String("refs/pull/124/head")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Ref: vela.String("refs/heads/main")})
+
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{constants.StatusSuccess},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+	}
+
+	client, err := p.client("")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	repos, err := (&Repo{Names: []string{"go-vela/hello-world#123", "go-vela/hello-world#125"}}).Parse("main")
+	if err != nil {
+		t.Errorf("Parse returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.search(context.Background(), client, repos[0])
+	if err != nil {
+		t.Errorf("search returned err: %v", err)
+	}
+
+	if got.GetNumber() != 2 {
+		t.Errorf("search is build %d, want 2", got.GetNumber())
+	}
+
+	got, err = p.search(context.Background(), client, repos[1])
+	if err != nil {
+		t.Errorf("search returned err: %v", err)
+	}
+
+	if got != nil {
+		t.Errorf("search is build %d, want nil", got.GetNumber())
+	}
+}
+
+func TestDownstream_Plugin_search_Schedule(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddSchedule("go-vela/hello-world", &api.Schedule{Name: vela.String("nightly"), Branch: vela.String("dev")})
+	s.AddSchedule("go-vela/hello-world", &api.Schedule{Name: vela.String("hourly")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("dev"), Event: vela.String(constants.EventSchedule), Deploy: vela.String("nightly")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Event: vela.String(constants.EventSchedule), Deploy: vela.String("nightly")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Event: vela.String(constants.EventSchedule), Deploy: vela.String("hourly")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("dev")})
+
+	// setup tests
+	tests := []struct {
+		schedule string
+		want     int64
+		failure  bool
+	}{
+		{schedule: "nightly", want: 1},
+		{schedule: "hourly", want: 3},
+		{schedule: "weekly", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.schedule, func(t *testing.T) {
+			p := &Plugin{
+				Build: &Build{
+					Event:    constants.EventPush,
+					Status:   []string{constants.StatusSuccess},
+					Schedule: test.schedule,
+				},
+				Config: &Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+				},
+			}
+
+			client, err := p.client("")
+			if err != nil {
+				t.Errorf("client returned err: %v", err)
+			}
+
+			repo := &Target{Repo: &api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world"), FullName: vela.String("go-vela/hello-world")}}
+
+			got, err := p.search(context.Background(), client, repo)
+			if test.failure {
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("search returned err %v, want %v", err, ErrNotFound)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("search returned err: %v", err)
+			}
+
+			if got.GetNumber() != test.want {
+				t.Errorf("search is build %d, want %d", got.GetNumber(), test.want)
+			}
+		})
+	}
+}
+
+func TestDownstream_Plugin_search_NegatedStatus(t *testing.T) {
+	// setup context
+	s := velatest.NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusFailure)})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusCanceled)})
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusRunning)})
+
+	p := &Plugin{
+		Build: &Build{
+			Event:  constants.EventPush,
+			Status: []string{"completed", "!canceled"},
+		},
+		Config: &Config{
+			Server: s.URL,
+			Token:  "superSecretVelaToken",
+		},
+	}
+
+	client, err := p.client("")
+	if err != nil {
+		t.Errorf("client returned err: %v", err)
+	}
+
+	// run test
+	got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+	if err != nil {
+		t.Errorf("search returned err: %v", err)
+	}
+
+	if got.GetNumber() != 1 {
+		t.Errorf("search is build %d, want 1", got.GetNumber())
+	}
+}
+
+func TestDownstream_Plugin_search_ServerStatus(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		status string
+		want   int64
+	}{
+		{status: "Failure", want: 1},
+		{status: constants.StatusPendingApproval, want: 2},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.status, func(t *testing.T) {
+			s := velatest.NewServer()
+			defer s.Close()
+
+			s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusFailure)})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusPendingApproval)})
+			s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String(constants.StatusSuccess)})
+
+			p := &Plugin{
+				Build: &Build{
+					Event:  constants.EventPush,
+					Status: []string{test.status},
+				},
+				Config: &Config{
+					Server: s.URL,
+					Token:  "superSecretVelaToken",
+				},
+			}
+
+			client, err := p.client("")
+			if err != nil {
+				t.Errorf("client returned err: %v", err)
+			}
+
+			got, err := p.search(context.Background(), client, &Target{Repo: s.Builds("go-vela/hello-world")[0].GetRepo()})
+			if err != nil {
+				t.Errorf("search returned err: %v", err)
+			}
+
+			if got.GetNumber() != test.want {
+				t.Errorf("search is build %d, want %d", got.GetNumber(), test.want)
+			}
+		})
+	}
+}
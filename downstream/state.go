@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+)
+
+// State represents the plugin configuration for recording the
+// builds triggered for an upstream build to a checkpoint file.
+//
+// When the upstream step is retried, the builds recorded for the
+// upstream build are resumed instead of being triggered again.
+type State struct {
+	// path to the file to record triggered builds to
+	Path string
+	// upstream build the triggered builds are recorded for,
+	// i.e. <org>/<repo>/<number> of the upstream build
+	Key string
+}
+
+// state represents the contents of the checkpoint file.
+type state struct {
+	// triggered builds by upstream build
+	Builds map[string][]string `json:"builds"`
+	// attempts of the triggered builds by upstream build and triggered build
+	Records map[string]map[string]*record `json:"records,omitempty"`
+}
+
+// record represents the attempts recorded for a triggered build.
+type record struct {
+	// number of the build that was restarted
+	Source int64 `json:"source,omitempty"`
+	// number of times the build was triggered for the repo
+	Attempts int `json:"attempts,omitempty"`
+	// numbers of the failed builds retried for the repo, oldest first
+	Retried []int64 `json:"retried,omitempty"`
+}
+
+// Load returns the builds recorded for the upstream build.
+//
+// No builds are returned when the file does not exist yet.
+func (s *State) Load() ([]*Triggered, error) {
+	logrus.Tracef("loading state for %s from %s", s.Key, s.Path)
+
+	st, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	triggered, err := ParseTriggered(st.Builds[s.Key]...)
+	if err != nil {
+		return nil, err
+	}
+
+	// restore the attempts recorded for the triggered builds so
+	// resumed builds do not reset the retries for the repos
+	for _, t := range triggered {
+		r, ok := st.Records[s.Key][t.String()]
+		if !ok {
+			continue
+		}
+
+		if r.Source > 0 {
+			t.Source = &api.Build{Number: vela.Int64(r.Source)}
+		}
+
+		t.Attempts = r.Attempts
+
+		for _, number := range r.Retried {
+			t.Retried = append(t.Retried, &api.Build{Number: vela.Int64(number)})
+		}
+	}
+
+	return triggered, nil
+}
+
+// Save records the provided builds for the upstream build, replacing
+// any builds previously recorded for the upstream build.
+//
+// The file is replaced atomically so an interrupted save
+// does not lose the builds recorded for other upstream builds.
+func (s *State) Save(triggered []*Triggered) error {
+	logrus.Tracef("saving state for %s to %s", s.Key, s.Path)
+
+	st, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	// create new list to store the recorded builds
+	builds := []string{}
+	records := make(map[string]*record)
+
+	for _, t := range triggered {
+		builds = append(builds, t.String())
+
+		r := &record{
+			Source:   t.Source.GetNumber(),
+			Attempts: t.Attempts,
+		}
+
+		for _, b := range t.Retried {
+			r.Retried = append(r.Retried, b.GetNumber())
+		}
+
+		records[t.String()] = r
+	}
+
+	st.Builds[s.Key] = builds
+	st.Records[s.Key] = records
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode state file: %w", err)
+	}
+
+	// write the state to a temporary file next to the state file
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write state file: %w", err)
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+
+		return fmt.Errorf("unable to write state file: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+
+		return fmt.Errorf("unable to write state file: %w", err)
+	}
+
+	// replace the state file with the temporary file
+	err = os.Rename(f.Name(), s.Path)
+	if err != nil {
+		os.Remove(f.Name())
+
+		return fmt.Errorf("unable to write state file: %w", err)
+	}
+
+	return nil
+}
+
+// Validate verifies the State is properly configured.
+func (s *State) Validate() error {
+	logrus.Trace("validating state configuration")
+
+	// verify state path is provided
+	if len(s.Path) == 0 {
+		return fmt.Errorf("no state file provided")
+	}
+
+	// verify the upstream build is provided
+	parts := strings.Split(s.Key, "/")
+	if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || len(parts[2]) == 0 {
+		return fmt.Errorf("invalid upstream build provided for state file: %q, VELA_REPO_FULL_NAME and VELA_BUILD_NUMBER must be set", s.Key)
+	}
+
+	return nil
+}
+
+// read returns the contents of the state file.
+func (s *State) read() (*state, error) {
+	st := &state{
+		Builds:  make(map[string][]string),
+		Records: make(map[string]map[string]*record),
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state file: %w", err)
+	}
+
+	err = json.Unmarshal(data, st)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse state file %s: %w", s.Path, err)
+	}
+
+	if st.Builds == nil {
+		st.Builds = make(map[string][]string)
+	}
+
+	if st.Records == nil {
+		st.Records = make(map[string]map[string]*record)
+	}
+
+	return st, nil
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+)
+
+func TestDownstream_State_Load_NoFile(t *testing.T) {
+	// setup types
+	s := &State{
+		Path: filepath.Join(t.TempDir(), "state.json"),
+		Key:  "go-vela/upstream/1",
+	}
+
+	// run test
+	got, err := s.Load()
+	if err != nil {
+		t.Errorf("Load returned err: %v", err)
+	}
+
+	if len(got) != 0 {
+		t.Errorf("Load is %v, want no builds", got)
+	}
+}
+
+func TestDownstream_State_Save(t *testing.T) {
+	// setup types
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	first := &State{Path: path, Key: "go-vela/upstream/1"}
+	second := &State{Path: path, Key: "go-vela/upstream/2"}
+
+	triggered, err := ParseTriggered("go-vela/hello-world/2", "prod:go-vela/hello-go/5")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	// run test
+	err = first.Save(triggered[:1])
+	if err != nil {
+		t.Errorf("Save returned err: %v", err)
+	}
+
+	err = second.Save(triggered[1:])
+	if err != nil {
+		t.Errorf("Save returned err: %v", err)
+	}
+
+	err = first.Save(triggered)
+	if err != nil {
+		t.Errorf("Save returned err: %v", err)
+	}
+
+	got, err := first.Load()
+	if err != nil {
+		t.Errorf("Load returned err: %v", err)
+	}
+
+	if len(got) != 2 || got[0].String() != "go-vela/hello-world/2" || got[1].String() != "prod:go-vela/hello-go/5" {
+		t.Errorf("Load is %v, want %v", got, triggered)
+	}
+
+	got, err = second.Load()
+	if err != nil {
+		t.Errorf("Load returned err: %v", err)
+	}
+
+	if len(got) != 1 || got[0].String() != "prod:go-vela/hello-go/5" {
+		t.Errorf("Load is %v, want %v", got, triggered[1:])
+	}
+}
+
+func TestDownstream_State_Save_Attempts(t *testing.T) {
+	// setup types
+	s := &State{
+		Path: filepath.Join(t.TempDir(), "state.json"),
+		Key:  "go-vela/upstream/1",
+	}
+
+	triggered, err := ParseTriggered("go-vela/hello-world/3", "go-vela/hello-go/5")
+	if err != nil {
+		t.Errorf("ParseTriggered returned err: %v", err)
+	}
+
+	triggered[0].Source = &api.Build{Number: vela.Int64(1)}
+	triggered[0].Attempts = 2
+	triggered[0].Retried = []*api.Build{{Number: vela.Int64(2)}}
+
+	// run test
+	err = s.Save(triggered)
+	if err != nil {
+		t.Errorf("Save returned err: %v", err)
+	}
+
+	got, err := s.Load()
+	if err != nil {
+		t.Errorf("Load returned err: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Load is %v, want %v", got, triggered)
+	}
+
+	if got[0].Source.GetNumber() != 1 || got[0].Attempts != 2 || len(got[0].Retried) != 1 || got[0].Retried[0].GetNumber() != 2 {
+		t.Errorf("Load is %+v, want source 1, 2 attempts and retried 2", got[0])
+	}
+
+	if got[1].Source != nil || got[1].Attempts != 0 || len(got[1].Retried) != 0 {
+		t.Errorf("Load is %+v, want no attempts recorded", got[1])
+	}
+}
+
+func TestDownstream_State_Load_Invalid(t *testing.T) {
+	// setup types
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	err := os.WriteFile(path, []byte("not json"), 0o600)
+	if err != nil {
+		t.Errorf("WriteFile returned err: %v", err)
+	}
+
+	s := &State{Path: path, Key: "go-vela/upstream/1"}
+
+	// run test
+	_, err = s.Load()
+	if err == nil {
+		t.Errorf("Load should have returned err")
+	}
+}
+
+func TestDownstream_State_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		state   *State
+		failure bool
+	}{
+		{
+			name:  "valid",
+			state: &State{Path: "state.json", Key: "go-vela/upstream/1"},
+		},
+		{
+			name:    "no path",
+			state:   &State{Key: "go-vela/upstream/1"},
+			failure: true,
+		},
+		{
+			name:    "no upstream build",
+			state:   &State{Path: "state.json", Key: "/"},
+			failure: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.state.Validate()
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Validate should have returned err")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Validate returned err: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-vela/server/constants"
+)
+
+// validStatuses represents the statuses of a build.
+var validStatuses = []string{
+	constants.StatusCanceled,
+	constants.StatusError,
+	constants.StatusFailure,
+	constants.StatusKilled,
+	constants.StatusPending,
+	constants.StatusPendingApproval,
+	constants.StatusRunning,
+	constants.StatusSuccess,
+}
+
+// completedStatuses represents the statuses
+// of a build that finished running.
+var completedStatuses = []string{
+	constants.StatusCanceled,
+	constants.StatusError,
+	constants.StatusFailure,
+	constants.StatusKilled,
+	constants.StatusSuccess,
+}
+
+// serverStatuses represents the statuses the
+// Vela server is able to filter builds by.
+var serverStatuses = []string{
+	constants.StatusCanceled,
+	constants.StatusError,
+	constants.StatusFailure,
+	constants.StatusKilled,
+	constants.StatusPending,
+	constants.StatusRunning,
+	constants.StatusSuccess,
+}
+
+// statusGroups represents the groups of statuses
+// that may be provided in place of a status.
+var statusGroups = map[string][]string{
+	// any status of a build
+	"any": validStatuses,
+	// statuses of a build that finished running
+	"completed": completedStatuses,
+	// alias of the completed group
+	"terminal": completedStatuses,
+	// any status of a build other than success
+	"non-success": {
+		constants.StatusCanceled,
+		constants.StatusError,
+		constants.StatusFailure,
+		constants.StatusKilled,
+		constants.StatusPending,
+		constants.StatusPendingApproval,
+		constants.StatusRunning,
+	},
+}
+
+// validateStatuses verifies the provided list of statuses only
+// contains statuses or groups of statuses, optionally negated.
+func validateStatuses(list []string) error {
+	for _, status := range list {
+		// capture the status without the negation, i.e. failure from !failure
+		name, negated := strings.CutPrefix(status, "!")
+
+		// verify the status is a status or group of statuses
+		_, group := statusGroups[strings.ToLower(name)]
+		if !group && !contains(validStatuses, name) {
+			return fmt.Errorf("invalid build status provided: %s", status)
+		}
+
+		// verify the negated status can match a build
+		if negated && strings.EqualFold(name, "any") {
+			return fmt.Errorf("invalid build status provided: %s matches no builds", status)
+		}
+	}
+
+	return nil
+}
+
+// matchStatus checks if the provided status matches the given list of
+// statuses. The status must match any status or group that is not
+// negated, if one is provided, and none of the negated statuses.
+//
+// i.e. [completed, !canceled] matches builds that finished running
+// without being canceled and [!failure] matches all but failed builds.
+//
+// An empty list of statuses matches no status.
+func matchStatus(list []string, status string) bool {
+	// check if any statuses are provided
+	if len(list) == 0 {
+		return false
+	}
+
+	// track if the status matched a status that is not negated
+	matched := false
+	// track if a status that is not negated was provided
+	required := false
+
+	for _, item := range list {
+		name, negated := strings.CutPrefix(item, "!")
+
+		// check if the status is excluded by a negated status
+		if negated {
+			if inStatus(name, status) {
+				return false
+			}
+
+			continue
+		}
+
+		required = true
+
+		if inStatus(name, status) {
+			matched = true
+		}
+	}
+
+	return matched || !required
+}
+
+// serverStatus returns the status the Vela server is able to filter
+// builds by for the provided list of statuses, or an empty string
+// when the list is not a single status without a negation or the
+// server is unable to filter by the status, i.e. pending approval.
+func serverStatus(list []string) string {
+	// check if a single status is provided
+	if len(list) != 1 {
+		return ""
+	}
+
+	// the Vela server only accepts lowercase statuses
+	status := strings.ToLower(list[0])
+
+	// check if the server is able to filter by the status
+	if !slices.Contains(serverStatuses, status) {
+		return ""
+	}
+
+	return status
+}
+
+// inStatus checks if the provided status is the
+// same as the given status or in the given group.
+func inStatus(name, status string) bool {
+	return strings.EqualFold(name, status) || contains(statusGroups[strings.ToLower(name)], status)
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-vela/server/constants"
+)
+
+func TestDownstream_validateStatuses(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		statuses []string
+		failure  bool
+	}{
+		{statuses: []string{constants.StatusSuccess, constants.StatusFailure}},
+		{statuses: []string{"any"}},
+		{statuses: []string{"!failure", "!canceled"}},
+		{statuses: []string{"terminal"}},
+		{statuses: []string{"completed", "!canceled"}},
+		{statuses: []string{"non-success"}},
+		{statuses: []string{"!pending approval"}},
+		{statuses: []string{"foo"}, failure: true},
+		{statuses: []string{"!foo"}, failure: true},
+		{statuses: []string{"!any"}, failure: true},
+		{statuses: []string{"!"}, failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(strings.Join(test.statuses, ","), func(t *testing.T) {
+			err := validateStatuses(test.statuses)
+			if test.failure != (err != nil) {
+				t.Errorf("validateStatuses returned err %v, want failure %t", err, test.failure)
+			}
+		})
+	}
+}
+
+func TestDownstream_matchStatus(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		statuses []string
+		matches  []string
+		misses   []string
+	}{
+		{
+			statuses: []string{constants.StatusSuccess, constants.StatusFailure},
+			matches:  []string{constants.StatusSuccess, constants.StatusFailure},
+			misses:   []string{constants.StatusCanceled, constants.StatusRunning},
+		},
+		{
+			statuses: []string{"any"},
+			matches:  []string{constants.StatusSuccess, constants.StatusRunning, constants.StatusPendingApproval},
+		},
+		{
+			statuses: []string{"!canceled"},
+			matches:  []string{constants.StatusSuccess, constants.StatusFailure, constants.StatusRunning},
+			misses:   []string{constants.StatusCanceled},
+		},
+		{
+			statuses: []string{"!failure", "!canceled"},
+			matches:  []string{constants.StatusSuccess, constants.StatusKilled},
+			misses:   []string{constants.StatusFailure, constants.StatusCanceled},
+		},
+		{
+			statuses: []string{"completed", "!canceled"},
+			matches:  []string{constants.StatusSuccess, constants.StatusError},
+			misses:   []string{constants.StatusCanceled, constants.StatusRunning, constants.StatusPending},
+		},
+		{
+			statuses: []string{"terminal"},
+			matches:  []string{constants.StatusSuccess, constants.StatusCanceled},
+			misses:   []string{constants.StatusRunning},
+		},
+		{
+			statuses: []string{"non-success"},
+			matches:  []string{constants.StatusFailure, constants.StatusRunning},
+			misses:   []string{constants.StatusSuccess},
+		},
+		{
+			statuses: []string{"!completed"},
+			matches:  []string{constants.StatusRunning, constants.StatusPending},
+			misses:   []string{constants.StatusSuccess, constants.StatusFailure},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(strings.Join(test.statuses, ","), func(t *testing.T) {
+			for _, status := range test.matches {
+				if !matchStatus(test.statuses, status) {
+					t.Errorf("matchStatus %v should have matched %s", test.statuses, status)
+				}
+			}
+
+			for _, status := range test.misses {
+				if matchStatus(test.statuses, status) {
+					t.Errorf("matchStatus %v should not have matched %s", test.statuses, status)
+				}
+			}
+		})
+	}
+}
+
+func TestDownstream_matchStatus_Empty(t *testing.T) {
+	// run test
+	for _, status := range validStatuses {
+		if matchStatus(nil, status) {
+			t.Errorf("matchStatus without statuses should not have matched %s", status)
+		}
+	}
+}
+
+func TestDownstream_serverStatus(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		statuses []string
+		want     string
+	}{
+		{statuses: []string{constants.StatusSuccess}, want: constants.StatusSuccess},
+		{statuses: []string{"Success"}, want: constants.StatusSuccess},
+		{statuses: []string{constants.StatusPendingApproval}},
+		{statuses: []string{constants.StatusSuccess, constants.StatusFailure}},
+		{statuses: []string{"any"}},
+		{statuses: []string{"completed"}},
+		{statuses: []string{"!failure"}},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(strings.Join(test.statuses, ","), func(t *testing.T) {
+			got := serverStatus(test.statuses)
+			if got != test.want {
+				t.Errorf("serverStatus is %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDownstream_Plugin_targeted(t *testing.T) {
+	// setup types
+	p := &Plugin{
+		Build: &Build{
+			TargetStatus: []string{"!failure"},
+		},
+	}
+
+	// run test
+	if !p.targeted(constants.StatusSuccess) {
+		t.Errorf("targeted should have matched %s", constants.StatusSuccess)
+	}
+
+	if p.targeted(constants.StatusFailure) {
+		t.Errorf("targeted should not have matched %s", constants.StatusFailure)
+	}
+
+	if p.targeted(constants.StatusRunning) {
+		t.Errorf("targeted should not have matched incomplete %s", constants.StatusRunning)
+	}
+
+	p.Build.TargetStatus = nil
+
+	if p.targeted(constants.StatusFailure) || p.targeted(constants.StatusSuccess) {
+		t.Errorf("targeted should not have matched without target statuses")
+	}
+
+	p.Build.TargetStatus = []string{constants.StatusRunning}
+
+	if !p.targeted(constants.StatusRunning) {
+		t.Errorf("targeted should have matched %s", constants.StatusRunning)
+	}
+}
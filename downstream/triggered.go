@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+// Triggered represents a build triggered for a downstream repo.
+type Triggered struct {
+	// downstream repo the build was triggered for
+	Repo *Target
+	// build that was restarted
+	Source *api.Build
+	// build created from the restart
+	Build *api.Build
+	// number of times the build was triggered for the repo
+	Attempts int
+	// failed builds that were retried for the repo, oldest first
+	Retried []*api.Build
+}
+
+// ParseTriggered parses the provided <org>/<repo>/<number> builds
+// into triggered builds to wait on, report or cancel.
+//
+// Each build may be prefixed with the name of a
+// config profile, i.e. <profile>:<org>/<repo>/<number>.
+func ParseTriggered(names ...string) ([]*Triggered, error) {
+	logrus.Trace("parsing builds from provided configuration")
+
+	// create new list to store the parsed builds
+	triggered := []*Triggered{}
+
+	for _, name := range names {
+		logrus.Tracef("parsing build %s", name)
+
+		// create new repo type to store parsed repo information
+		repo := &Target{Repo: new(api.Repo)}
+
+		// check if a profile was provided with profile:org/repo/number
+		fullName := name
+
+		profile, rest, ok := strings.Cut(name, ":")
+		if ok {
+			repo.Profile = profile
+			fullName = rest
+		}
+
+		// split the build on / to account for org/repo/number as input
+		parts := strings.Split(fullName, "/")
+		if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || (ok && len(profile) == 0) {
+			return nil, fmt.Errorf("%w: invalid <org>/<repo>/<number> build provided: %s", ErrConfig, name)
+		}
+
+		number, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || number < 1 {
+			return nil, fmt.Errorf("%w: invalid build number provided: %s", ErrConfig, name)
+		}
+
+		repo.SetOrg(parts[0])
+		repo.SetName(parts[1])
+		repo.SetFullName(fmt.Sprintf("%s/%s", parts[0], parts[1]))
+
+		build := new(api.Build)
+		build.SetNumber(number)
+
+		triggered = append(triggered, &Triggered{Repo: repo, Build: build})
+	}
+
+	return triggered, nil
+}
+
+// attempts returns the number of times the build was triggered for
+// the repo, which is at least once for every triggered build.
+func (t *Triggered) attempts() int {
+	return max(t.Attempts, 1)
+}
+
+// String returns the triggered build in the form accepted
+// by ParseTriggered, i.e. <profile>:<org>/<repo>/<number>.
+func (t *Triggered) String() string {
+	name := fmt.Sprintf("%s/%d", t.Repo.GetFullName(), t.Build.GetNumber())
+
+	// check if a profile is provided for the repo
+	if len(t.Repo.Profile) > 0 {
+		return fmt.Sprintf("%s:%s", t.Repo.Profile, name)
+	}
+
+	return name
+}
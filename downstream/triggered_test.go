@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package downstream
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDownstream_ParseTriggered(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		want    string
+		profile string
+	}{
+		{
+			name: "go-vela/hello-world/1",
+			want: "go-vela/hello-world/1",
+		},
+		{
+			name:    "prod:go-vela/hello-world/12",
+			want:    "prod:go-vela/hello-world/12",
+			profile: "prod",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseTriggered(test.name)
+			if err != nil {
+				t.Errorf("ParseTriggered returned err: %v", err)
+			}
+
+			if len(got) != 1 {
+				t.Fatalf("ParseTriggered returned %d builds, want 1", len(got))
+			}
+
+			if got[0].String() != test.want {
+				t.Errorf("ParseTriggered is %s, want %s", got[0], test.want)
+			}
+
+			if got[0].Repo.Profile != test.profile {
+				t.Errorf("ParseTriggered profile is %s, want %s", got[0].Repo.Profile, test.profile)
+			}
+		})
+	}
+}
+
+func TestDownstream_ParseTriggered_Failure(t *testing.T) {
+	// setup tests
+	tests := []string{
+		"go-vela/hello-world",
+		"go-vela/hello-world/latest",
+		"go-vela/hello-world/0",
+		"go-vela//1",
+		":go-vela/hello-world/1",
+		"go-vela/hello-world/1/2",
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			_, err := ParseTriggered(test)
+			if !errors.Is(err, ErrConfig) {
+				t.Errorf("ParseTriggered returned err %v, want %v", err, ErrConfig)
+			}
+		})
+	}
+}
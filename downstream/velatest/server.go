@@ -0,0 +1,566 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package velatest provides an in-process fake Vela server for
+// testing code that triggers and waits on downstream builds.
+//
+// The server models repos, builds, pagination of build lists
+// and status transitions of builds without a real Vela server:
+//
+//	s := velatest.NewServer()
+//	defer s.Close()
+//
+//	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+//	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main"), Status: vela.String("success")})
+//	s.SetTransitions("go-vela/hello-world", "running", "success")
+package velatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+)
+
+const (
+	// Version represents the version reported by the server.
+	Version = "v0.27.5"
+
+	// User represents the name of the user every token belongs to.
+	User = "octocat"
+
+	// Token represents the access token issued by the server.
+	Token = "superSecretAccessToken"
+)
+
+// listStatuses represents the statuses the server filters builds by.
+var listStatuses = []string{
+	constants.StatusCanceled,
+	constants.StatusError,
+	constants.StatusFailure,
+	constants.StatusKilled,
+	constants.StatusPending,
+	constants.StatusRunning,
+	constants.StatusSuccess,
+}
+
+// Server represents a fake Vela server.
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// repos added to the server by full name
+	repos map[string]*api.Repo
+	// builds added to the server by repo full name
+	builds map[string][]*build
+	// schedules added to the server by repo full name and name
+	schedules map[string]map[string]*api.Schedule
+	// statuses restarted builds move through by repo full name
+	transitions map[string][]string
+	// statuses the next restarted builds move through by repo full name
+	queued map[string][][]string
+	// number of requests received by route pattern
+	calls map[string]int
+}
+
+// build represents a build stored in the server.
+type build struct {
+	*api.Build
+
+	// statuses the build moves through, one for every time it is read
+	statuses []string
+}
+
+// NewServer creates and starts a fake Vela server.
+//
+// The caller should call Close when finished to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		repos:       make(map[string]*api.Repo),
+		builds:      make(map[string][]*build),
+		schedules:   make(map[string]map[string]*api.Schedule),
+		transitions: make(map[string][]string),
+		queued:      make(map[string][][]string),
+		calls:       make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /authenticate/token", s.authenticate)
+	mux.HandleFunc("GET /token-refresh", s.refresh)
+	mux.HandleFunc("GET /version", s.version)
+	mux.HandleFunc("GET /api/v1/user", s.user)
+	mux.HandleFunc("GET /api/v1/repos/{org}/{repo}", s.getRepo)
+	mux.HandleFunc("GET /api/v1/repos/{org}/{repo}/builds", s.listBuilds)
+	mux.HandleFunc("GET /api/v1/repos/{org}/{repo}/builds/{build}", s.getBuild)
+	mux.HandleFunc("POST /api/v1/repos/{org}/{repo}/builds/{build}", s.restartBuild)
+	mux.HandleFunc("DELETE /api/v1/repos/{org}/{repo}/builds/{build}/cancel", s.cancelBuild)
+	mux.HandleFunc("POST /api/v1/repos/{org}/{repo}/builds/{build}/approve", s.approveBuild)
+	mux.HandleFunc("GET /api/v1/schedules/{org}/{repo}/{schedule}", s.getSchedule)
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+
+		s.mu.Lock()
+		s.calls[pattern]++
+		s.mu.Unlock()
+
+		// verify the request is authenticated for the API
+		if strings.HasPrefix(r.URL.Path, "/api/") && len(r.Header.Get("Authorization")) == 0 {
+			writeError(w, http.StatusUnauthorized, "no authorization provided")
+
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	}))
+
+	return s
+}
+
+// AddRepo adds the provided repo to the server.
+//
+// The repo is active with a main default branch unless provided.
+func (s *Server) AddRepo(r *api.Repo) *api.Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Active == nil {
+		r.SetActive(true)
+	}
+
+	if r.Branch == nil {
+		r.SetBranch("main")
+	}
+
+	r.SetFullName(fmt.Sprintf("%s/%s", r.GetOrg(), r.GetName()))
+
+	s.repos[r.GetFullName()] = r
+
+	return r
+}
+
+// AddSchedule adds the provided schedule to the repo with the provided full name.
+//
+// The schedule is active on the default branch of the repo unless provided.
+func (s *Server) AddSchedule(repo string, sc *api.Schedule) *api.Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sc.Active == nil {
+		sc.SetActive(true)
+	}
+
+	if r, ok := s.repos[repo]; ok {
+		sc.SetRepo(r)
+
+		if sc.Branch == nil {
+			sc.SetBranch(r.GetBranch())
+		}
+	}
+
+	if s.schedules[repo] == nil {
+		s.schedules[repo] = make(map[string]*api.Schedule)
+	}
+
+	s.schedules[repo][sc.GetName()] = sc
+
+	return sc
+}
+
+// AddBuild adds the provided build to the repo with the provided full name.
+//
+// The build is numbered after the last build of the repo unless provided
+// and moves through the provided statuses each time it is read.
+func (s *Server) AddBuild(repo string, b *api.Build, statuses ...string) *api.Build {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if b.Number == nil {
+		b.SetNumber(int64(len(s.builds[repo]) + 1))
+	}
+
+	if b.Event == nil {
+		b.SetEvent(constants.EventPush)
+	}
+
+	if b.Status == nil {
+		b.SetStatus(constants.StatusSuccess)
+	}
+
+	if b.Created == nil {
+		b.SetCreated(time.Now().UTC().Unix())
+	}
+
+	if r, ok := s.repos[repo]; ok {
+		b.SetRepo(r)
+	}
+
+	s.builds[repo] = append(s.builds[repo], &build{Build: b, statuses: statuses})
+
+	return b
+}
+
+// SetTransitions sets the statuses builds restarted for the repo
+// with the provided full name move through, one for every time
+// they are read. Restarted builds are pending until then.
+func (s *Server) SetTransitions(repo string, statuses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transitions[repo] = statuses
+}
+
+// QueueTransitions queues the statuses the next build restarted for the
+// repo with the provided full name moves through, taking precedence over
+// the statuses from SetTransitions. Each call queues a single restart.
+func (s *Server) QueueTransitions(repo string, statuses ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queued[repo] = append(s.queued[repo], statuses)
+}
+
+// Build returns a copy of the build with the provided
+// number for the repo with the provided full name.
+func (s *Server) Build(repo string, number int64) *api.Build {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.build(repo, number)
+	if b == nil {
+		return nil
+	}
+
+	c := *b.Build
+
+	return &c
+}
+
+// Builds returns copies of all builds for the
+// repo with the provided full name, oldest first.
+func (s *Server) Builds(repo string) []*api.Build {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	builds := []*api.Build{}
+
+	for _, b := range s.builds[repo] {
+		c := *b.Build
+
+		builds = append(builds, &c)
+	}
+
+	return builds
+}
+
+// Calls returns the number of requests received for the
+// provided route pattern, i.e. "GET /api/v1/repos/{org}/{repo}/builds".
+func (s *Server) Calls(pattern string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[pattern]
+}
+
+// build returns the stored build with the provided number
+// for the repo with the provided full name.
+//
+// The caller must hold the lock of the server.
+func (s *Server) build(repo string, number int64) *build {
+	for _, b := range s.builds[repo] {
+		if b.GetNumber() == number {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// authenticate exchanges a personal access token for an access token.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) {
+	if len(r.Header.Get("Token")) == 0 {
+		writeError(w, http.StatusUnauthorized, "no token provided")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"token": Token})
+}
+
+// refresh exchanges a refresh token for an access token.
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+	_, err := r.Cookie(constants.RefreshTokenName)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "no refresh token provided")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"token": Token})
+}
+
+// version returns the version of the server.
+func (s *Server) version(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"canonical": Version})
+}
+
+// user returns the user the token belongs to.
+func (s *Server) user(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"name": User})
+}
+
+// getRepo returns the requested repo.
+func (s *Server) getRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.repos[fullName(r)]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unable to retrieve repo %s", fullName(r)))
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, repo)
+}
+
+// getSchedule returns the requested schedule.
+func (s *Server) getSchedule(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc, ok := s.schedules[fullName(r)][r.PathValue("schedule")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unable to retrieve schedule %s for repo %s", r.PathValue("schedule"), fullName(r)))
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sc)
+}
+
+// listBuilds returns a page of builds for the requested repo, newest first,
+// filtered by the branch, event, status, before and after query parameters.
+func (s *Server) listBuilds(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.repos[fullName(r)]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unable to retrieve repo %s", fullName(r)))
+
+		return
+	}
+
+	query := r.URL.Query()
+
+	// verify the status is a status the Vela server filters by
+	status := query.Get("status")
+	if len(status) > 0 && !slices.Contains(listStatuses, status) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unable to process status %s: invalid status type provided", status))
+
+		return
+	}
+
+	before, _ := strconv.ParseInt(query.Get("before"), 10, 64)
+	after, _ := strconv.ParseInt(query.Get("after"), 10, 64)
+
+	builds := []*api.Build{}
+
+	for _, b := range slices.Backward(s.builds[fullName(r)]) {
+		switch {
+		case len(query.Get("branch")) > 0 && b.GetBranch() != query.Get("branch"):
+			continue
+		case len(query.Get("event")) > 0 && b.GetEvent() != query.Get("event"):
+			continue
+		case len(status) > 0 && b.GetStatus() != status:
+			continue
+		case before > 0 && b.GetCreated() >= before:
+			continue
+		case after > 0 && b.GetCreated() <= after:
+			continue
+		}
+
+		builds = append(builds, b.Build)
+	}
+
+	// capture the requested page of builds
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = 10
+	}
+
+	perPage = min(perPage, 100)
+
+	start := min((page-1)*perPage, len(builds))
+	end := min(start+perPage, len(builds))
+
+	// link to the next page when more builds exist
+	if end < len(builds) {
+		next := *r.URL
+		query.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = query.Encode()
+
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+	}
+
+	writeJSON(w, http.StatusOK, builds[start:end])
+}
+
+// getBuild returns the requested build after moving it to its next status.
+//
+// A build pending approval stays pending approval until it is approved.
+func (s *Server) getBuild(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.requested(w, r)
+	if b == nil {
+		return
+	}
+
+	if len(b.statuses) > 0 && b.GetStatus() != constants.StatusPendingApproval {
+		b.SetStatus(b.statuses[0])
+
+		b.statuses = b.statuses[1:]
+	}
+
+	writeJSON(w, http.StatusOK, b.Build)
+}
+
+// restartBuild creates a new pending build from the requested build.
+func (s *Server) restartBuild(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.requested(w, r)
+	if b == nil {
+		return
+	}
+
+	restarted := *b.Build
+
+	restarted.SetNumber(int64(len(s.builds[fullName(r)]) + 1))
+	restarted.SetParent(b.GetNumber())
+	restarted.SetSender(User)
+	restarted.SetStatus(constants.StatusPending)
+	restarted.SetCreated(time.Now().UTC().Unix())
+	restarted.SetStarted(0)
+	restarted.SetFinished(0)
+
+	// capture the statuses the restarted build moves through
+	statuses := slices.Clone(s.transitions[fullName(r)])
+
+	if queued := s.queued[fullName(r)]; len(queued) > 0 {
+		statuses = queued[0]
+
+		s.queued[fullName(r)] = queued[1:]
+	}
+
+	s.builds[fullName(r)] = append(s.builds[fullName(r)], &build{
+		Build:    &restarted,
+		statuses: statuses,
+	})
+
+	writeJSON(w, http.StatusCreated, &restarted)
+}
+
+// cancelBuild cancels the requested build when it has not completed,
+// responding with bad request for a completed build like the Vela server.
+func (s *Server) cancelBuild(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.requested(w, r)
+	if b == nil {
+		return
+	}
+
+	switch b.GetStatus() {
+	case constants.StatusPending, constants.StatusPendingApproval, constants.StatusRunning:
+		b.SetStatus(constants.StatusCanceled)
+
+		b.statuses = nil
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("found build %s/%d but its status was %s", fullName(r), b.GetNumber(), b.GetStatus()))
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, b.Build)
+}
+
+// approveBuild approves the requested build when it is pending approval.
+func (s *Server) approveBuild(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.requested(w, r)
+	if b == nil {
+		return
+	}
+
+	if b.GetStatus() != constants.StatusPendingApproval {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("build %s/%d is not pending approval", fullName(r), b.GetNumber()))
+
+		return
+	}
+
+	b.SetStatus(constants.StatusPending)
+	b.SetApprovedBy(User)
+	b.SetApprovedAt(time.Now().UTC().Unix())
+
+	writeJSON(w, http.StatusOK, fmt.Sprintf("Successfully approved build %s/%d", fullName(r), b.GetNumber()))
+}
+
+// requested returns the stored build for the request
+// or writes a not found error when it does not exist.
+//
+// The caller must hold the lock of the server.
+func (s *Server) requested(w http.ResponseWriter, r *http.Request) *build {
+	number, err := strconv.ParseInt(r.PathValue("build"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid build number %s", r.PathValue("build")))
+
+		return nil
+	}
+
+	b := s.build(fullName(r), number)
+	if b == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unable to retrieve build %s/%d", fullName(r), number))
+
+		return nil
+	}
+
+	return b
+}
+
+// fullName returns the full name of the repo for the request.
+func fullName(r *http.Request) string {
+	return fmt.Sprintf("%s/%s", r.PathValue("org"), r.PathValue("repo"))
+}
+
+// writeJSON writes the provided value as the JSON response.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the provided message as the JSON error response.
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package velatest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+)
+
+// request sends an authenticated request to the provided path of the server.
+func request(t *testing.T, s *Server, method, path string, v any) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+path, nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned err: %v", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+Token)
+
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Do returned err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if v != nil {
+		err = json.NewDecoder(resp.Body).Decode(v)
+		if err != nil {
+			t.Fatalf("Decode returned err: %v", err)
+		}
+	}
+
+	return resp
+}
+
+func TestVelatest_Server_listBuilds(t *testing.T) {
+	// setup context
+	s := NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+
+	for range 12 {
+		s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("main")})
+	}
+
+	s.AddBuild("go-vela/hello-world", &api.Build{Branch: vela.String("dev")})
+
+	// run test
+	builds := []api.Build{}
+
+	resp := request(t, s, http.MethodGet, "/api/v1/repos/go-vela/hello-world/builds?branch=main&per_page=10", &builds)
+
+	if len(builds) != 10 || builds[0].GetNumber() != 12 {
+		t.Errorf("listBuilds returned %d builds starting at %d, want 10 starting at 12", len(builds), builds[0].GetNumber())
+	}
+
+	if !strings.Contains(resp.Header.Get("Link"), `page=2`) {
+		t.Errorf("listBuilds Link is %s, want next page 2", resp.Header.Get("Link"))
+	}
+
+	resp = request(t, s, http.MethodGet, "/api/v1/repos/go-vela/hello-world/builds?branch=main&per_page=10&page=2", &builds)
+
+	if len(builds) != 2 || builds[1].GetNumber() != 1 {
+		t.Errorf("listBuilds returned %d builds ending at %d, want 2 ending at 1", len(builds), builds[1].GetNumber())
+	}
+
+	if len(resp.Header.Get("Link")) > 0 {
+		t.Errorf("listBuilds Link is %s, want no next page", resp.Header.Get("Link"))
+	}
+
+	if s.Calls("GET /api/v1/repos/{org}/{repo}/builds") != 2 {
+		t.Errorf("Calls is %d, want 2", s.Calls("GET /api/v1/repos/{org}/{repo}/builds"))
+	}
+}
+
+func TestVelatest_Server_listBuilds_Status(t *testing.T) {
+	// setup context
+	s := NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{Status: vela.String(constants.StatusSuccess)})
+
+	// run test
+	for _, status := range []string{"Success", "pending%20approval", "completed"} {
+		resp := request(t, s, http.MethodGet, "/api/v1/repos/go-vela/hello-world/builds?status="+status, nil)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("listBuilds for status %s returned %d, want %d", status, resp.StatusCode, http.StatusBadRequest)
+		}
+	}
+
+	builds := []api.Build{}
+
+	resp := request(t, s, http.MethodGet, "/api/v1/repos/go-vela/hello-world/builds?status=success", &builds)
+	if resp.StatusCode != http.StatusOK || len(builds) != 1 {
+		t.Errorf("listBuilds returned %d with %d builds, want %d with 1", resp.StatusCode, len(builds), http.StatusOK)
+	}
+}
+
+func TestVelatest_Server_restartBuild(t *testing.T) {
+	// setup context
+	s := NewServer()
+	defer s.Close()
+
+	s.AddRepo(&api.Repo{Org: vela.String("go-vela"), Name: vela.String("hello-world")})
+	s.AddBuild("go-vela/hello-world", &api.Build{})
+	s.SetTransitions("go-vela/hello-world", constants.StatusRunning, constants.StatusSuccess)
+
+	// run test
+	build := new(api.Build)
+
+	resp := request(t, s, http.MethodPost, "/api/v1/repos/go-vela/hello-world/builds/1", build)
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("restartBuild returned %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+
+	for _, want := range []string{constants.StatusPending, constants.StatusRunning, constants.StatusSuccess, constants.StatusSuccess} {
+		if build.GetStatus() != want {
+			t.Errorf("build 2 status is %s, want %s", build.GetStatus(), want)
+		}
+
+		request(t, s, http.MethodGet, "/api/v1/repos/go-vela/hello-world/builds/2", build)
+	}
+}
+
+func TestVelatest_Server_Unauthorized(t *testing.T) {
+	// setup context
+	s := NewServer()
+	defer s.Close()
+
+	// run test
+	resp, err := s.Client().Get(s.URL + "/api/v1/user")
+	if err != nil {
+		t.Fatalf("Get returned err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Get returned %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}